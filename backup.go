@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/dedis/drand/beacon"
+	"github.com/dedis/drand/fs"
+	"github.com/dedis/drand/key"
+	"github.com/nikkolasg/slog"
+	"github.com/urfave/cli"
+)
+
+// backupPassphraseEnv holds the passphrase of the backup archives, for the
+// commands run without a terminal.
+const backupPassphraseEnv = "DRAND_BACKUP_PASSPHRASE"
+
+var backupPassphraseFlag = cli.BoolFlag{
+	Name: "backup-passphrase",
+	Usage: "Encrypt the backup archive with a passphrase, asked on the terminal " +
+		"or read from the " + backupPassphraseEnv + " environment variable.",
+}
+
+var backupKeyFileFlag = cli.StringFlag{
+	Name:  "backup-key-file",
+	Usage: "Encrypt the backup archive with the content of the given file, at least 32 random bytes.",
+}
+
+var withDBFlag = cli.BoolFlag{
+	Name:  "with-db",
+	Usage: "Include the beacon database in the backup. The daemon must be stopped.",
+}
+
+var forceFlag = cli.BoolFlag{
+	Name:  "force",
+	Usage: "Overwrite the keys and the beacon database already present in the config folder.",
+}
+
+// backupKey returns the key of the backup archive given on the command line.
+func backupKey(c *cli.Context) *key.StoreKey {
+	k := storeKey(c, backupPassphraseFlag.Name, backupKeyFileFlag.Name, backupPassphraseEnv, "Passphrase of the backup: ")
+	if k == nil {
+		slog.Fatal("drand: backups are encrypted, use --backup-passphrase or --backup-key-file")
+	}
+	return k
+}
+
+// backupCmd saves the keys, the share, the distributed key, the group and
+// optionally the beacon database of the node in one encrypted archive.
+func backupCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatalUsage("backup requires the path of the archive to write")
+	}
+	conf := contextToConfig(c)
+	k := backupKey(c)
+	b, err := key.NewBackup(keyStore(c, conf.ConfigFolder()))
+	checkUnlocked(err)
+	if err != nil {
+		slog.Fatalf("drand: can't read the keys to back up: %s", err)
+	}
+	if c.Bool(withDBFlag.Name) {
+		if b.DB, err = beacon.BoltSnapshot(conf.DBFolder()); err != nil {
+			slog.Fatalf("drand: can't back up the beacon database: %s", err)
+		}
+	}
+	var buff bytes.Buffer
+	manifest, err := b.Write(&buff, k)
+	if err != nil {
+		slog.Fatalf("drand: can't write the backup: %s", err)
+	}
+	archive := c.Args().First()
+	fd, err := fs.CreateSecureFile(archive)
+	if err != nil {
+		slog.Fatalf("drand: can't create %s: %s", archive, err)
+	}
+	defer fd.Close()
+	if _, err := fd.Write(buff.Bytes()); err != nil {
+		slog.Fatalf("drand: can't write %s: %s", archive, err)
+	}
+	printInfo("drand: backup of %d files written to %s", len(manifest.Files), archive)
+	printInfo("drand: integrity hash %s", manifest.Hash())
+	return nil
+}
+
+// restoreCmd restores the content of a backup archive in the config folder.
+// The archive is fully checked, including the share against the distributed
+// key of the group, before anything is written.
+func restoreCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatalUsage("restore requires the path of the archive to read")
+	}
+	conf := contextToConfig(c)
+	k := backupKey(c)
+	archive := c.Args().First()
+	fd, err := os.Open(archive)
+	if err != nil {
+		slog.Fatalf("drand: can't open %s: %s", archive, err)
+	}
+	b, manifest, err := key.ReadBackup(fd, k)
+	fd.Close()
+	if err != nil {
+		slog.Fatalf("drand: can't read the backup: %s", err)
+	}
+	if err := b.Verify(); err != nil {
+		slog.Fatalf("drand: invalid backup: %s", err)
+	}
+	slog.Infof("drand: backup of %s created at %s, integrity hash %s", manifest.Address, manifest.Created, manifest.Hash())
+
+	store := keyStore(c, conf.ConfigFolder())
+	dbPath := path.Join(conf.DBFolder(), beacon.BoltFileName)
+	if !c.Bool(forceFlag.Name) {
+		if _, err := store.LoadKeyPair(); err == nil || err == key.ErrEncrypted || err == key.ErrWrongKey {
+			slog.Fatal("drand: a key pair is already present, use --force to overwrite it")
+		}
+		if exists, _ := fs.Exists(dbPath); exists && b.DB != nil {
+			slog.Fatal("drand: a beacon database is already present, use --force to overwrite it")
+		}
+	}
+	if err := b.Restore(store); err != nil {
+		slog.Fatalf("drand: can't restore the keys: %s", err)
+	}
+	if b.DB != nil {
+		fs.CreateSecureFolder(conf.DBFolder())
+		tmp := dbPath + ".tmp"
+		if err := ioutil.WriteFile(tmp, b.DB, 0660); err != nil {
+			slog.Fatalf("drand: can't restore the beacon database: %s", err)
+		}
+		if err := os.Rename(tmp, dbPath); err != nil {
+			slog.Fatalf("drand: can't restore the beacon database: %s", err)
+		}
+	}
+	printInfo("drand: restored %d files from %s in %s", len(manifest.Files), archive, conf.ConfigFolder())
+	return nil
+}
@@ -12,8 +12,6 @@ import (
 	"github.com/dedis/drand/protobuf/crypto"
 	proto "github.com/dedis/drand/protobuf/drand"
 	"go.dedis.ch/kyber/v3/share"
-	"go.dedis.ch/kyber/v3/sign/bls"
-	"go.dedis.ch/kyber/v3/sign/tbls"
 
 	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
@@ -38,6 +36,8 @@ type Handler struct {
 	conf *Config
 	// to communicate with other drand peers
 	client net.InternalClient
+	// options of the calls to other drand peers
+	callOpts []net.CallOption
 	// where to store the new randomness beacon
 	store Store
 	// to sign beacons
@@ -65,7 +65,10 @@ type Handler struct {
 
 	ticker *time.Ticker
 	close  chan bool
-	addr   string
+	// tracks the loop and the rounds running, so Stop can wait for them
+	// before closing the store
+	running sync.WaitGroup
+	addr    string
 	// group id to embed in all beacons
 	// XXX temporary solution to change when we really want flexible groups
 	id      int32
@@ -76,28 +79,32 @@ type Handler struct {
 // NewHandler returns a fresh handler ready to serve and create randomness
 // beacon
 func NewHandler(c net.InternalClient, s Store, conf *Config) (*Handler, error) {
-	if conf.Private == nil || conf.Share == nil || conf.Group == nil || conf.Seed == nil {
+	if conf.Private == nil || conf.Share == nil || conf.Group == nil || conf.Seed == nil || conf.Group.Scheme == nil {
 		return nil, errors.New("beacon: invalid configuration")
 	}
 	idx, exists := conf.Group.Index(conf.Private.Public)
 	if !exists {
 		return nil, errors.New("beacon: keypair not included in the given group")
 	}
-	// XXX Make it parametrizable
-	id, exists := crypto.GroupToID(key.G1)
+	id, exists := crypto.GroupToID(conf.Group.Scheme.SigGroup)
 	if !exists {
 		return nil, errors.New("beacon: group has no registered ID")
 	}
 
 	addr := conf.Group.Nodes[idx].Addr
 
-	c.SetTimeout(conf.Group.Period) // wait on each call no more than the period
+	var callOpts []net.CallOption
+	if conf.Group.Period > 0 {
+		// wait on each call no more than the period
+		callOpts = append(callOpts, net.WithCallTimeout(conf.Group.Period))
+	}
 	return &Handler{
 		conf:      conf,
 		client:    c,
+		callOpts:  callOpts,
 		group:     conf.Group,
 		share:     conf.Share,
-		pub:       share.NewPubPoly(key.G2, key.G2.Point().Base(), conf.Share.Commits),
+		pub:       conf.Group.Scheme.PublicPoly(conf.Share.Commits),
 		index:     idx,
 		store:     s,
 		close:     make(chan bool),
@@ -129,8 +136,8 @@ func (h *Handler) ProcessBeacon(c context.Context, p *proto.BeaconRequest) (*pro
 	}
 
 	// 2- we dont catch up at least with invalid signature
-	msg := Message(p.PreviousRand, p.Round)
-	if err := tbls.Verify(key.Pairing, h.pub, msg, p.PartialRand); err != nil {
+	msg := Message(h.group, p.PreviousRand, p.Round)
+	if err := h.group.Scheme.PartialVerify(h.pub, msg, p.PartialRand); err != nil {
 		slog.Debugf("beacon: received invalid signature request")
 		return nil, err
 	}
@@ -160,6 +167,8 @@ func (h *Handler) ProcessBeacon(c context.Context, p *proto.BeaconRequest) (*pro
 // knows the current round it must execute. WARNING: It is not a bullet proof
 // solution, as a remote node could trick this beacon generator to start for an
 // outdated or far-in-the-future round. This is a starting point.
+// If the group has a genesis time, each round is started at the time it is
+// due, so the round of a beacon gives the time at which it was produced.
 //func (h *Handler) Loop(seed []byte, period time.Duration, catchup bool) {
 func (h *Handler) Run(period time.Duration, catchup bool) {
 	var goToNextRound = true // need to start one round anyway
@@ -170,7 +179,26 @@ func (h *Handler) Run(period time.Duration, catchup bool) {
 	winCh := make(chan roundInfo)
 	closingCh := make(chan bool)
 
+	if h.group.GenesisTime != 0 {
+		// rounds are tied to time: the ticks happen when the rounds are due,
+		// starting with the next one
+		next := h.group.TimeOfRound(h.group.RoundAt(time.Now()))
+		select {
+		case <-time.After(time.Until(next)):
+		case <-h.close:
+			return
+		}
+	}
+
 	h.Lock()
+	select {
+	case <-h.close:
+		h.Unlock()
+		return
+	default:
+	}
+	h.running.Add(1)
+	defer h.running.Done()
 	if !catchup {
 		// let's determine the previous signature we should build upon. It can
 		// be the seed or a guenuine one.
@@ -202,7 +230,12 @@ func (h *Handler) Run(period time.Duration, catchup bool) {
 				// it's OK here to potentially wait indefinitely since we anyway
 				// need to be up to date to continue so if we receive nothing we
 				// can't do anything else anyway.
-				b := <-h.catchupCh
+				var b Beacon
+				select {
+				case b = <-h.catchupCh:
+				case <-h.close:
+					return
+				}
 				slog.Infof("beacon: catched up on round %d (previous round %d)", b.Round, round)
 				// nextRound() automatically increases
 				h.setRound(b.Round - 1)
@@ -214,6 +247,7 @@ func (h *Handler) Run(period time.Duration, catchup bool) {
 			round = h.nextRound()
 			prevRand = h.getPreviousSignature()
 
+			h.running.Add(1)
 			go h.run(round, prevRand, winCh, closingCh)
 
 			goToNextRound = false
@@ -244,6 +278,9 @@ func (h *Handler) Run(period time.Duration, catchup bool) {
 			// the next tick,i.e. proper operational flow.
 			currentRoundFinished = true
 		case <-h.close:
+			// abandon the current round if it is still waiting for partial
+			// signatures
+			close(closingCh)
 			return
 		}
 	}
@@ -255,8 +292,9 @@ type roundInfo struct {
 }
 
 func (h *Handler) run(round uint64, prevRand []byte, winCh chan roundInfo, closeCh chan bool) {
+	defer h.running.Done()
 	slog.Debugf("beacon %s: next tick for round %d - time %s", h.addr, round, time.Now())
-	msg := Message(prevRand, round)
+	msg := Message(h.group, prevRand, round)
 	signature, err := h.signature(round, msg)
 	if err != nil {
 		slog.Debugf("beacon: round %d err creating/caching signature %s", round, err)
@@ -280,7 +318,7 @@ func (h *Handler) run(round uint64, prevRand []byte, winCh chan roundInfo, close
 		// return assuming there's a timeout on the connection
 		go func(i *key.Identity) {
 			//slog.Debugf("beacon: %s round %d: request new beacon to %s", h.addr, round, i.Address())
-			resp, err := h.client.NewBeacon(i, request)
+			resp, err := h.client.NewBeacon(i, request, h.callOpts...)
 			if err != nil {
 				slog.Debugf("beacon: %s round %d err from %s: %s", h.addr, round, i.Address(), err)
 				if strings.Contains(err.Error(), errOutOfRound) {
@@ -288,7 +326,7 @@ func (h *Handler) run(round uint64, prevRand []byte, winCh chan roundInfo, close
 				}
 				return
 			}
-			if err := tbls.Verify(key.Pairing, h.pub, msg, resp.PartialRand); err != nil {
+			if err := h.group.Scheme.PartialVerify(h.pub, msg, resp.PartialRand); err != nil {
 				slog.Debugf("beacon: invalid beacon response: %s", err)
 				return
 			}
@@ -312,12 +350,12 @@ func (h *Handler) run(round uint64, prevRand []byte, winCh chan roundInfo, close
 		}
 	}
 	//slog.Debugf("beacon: %s round %d -> out of the waiting loop (%d sigs)", h.addr, round, len(sigs))
-	finalSig, err := tbls.Recover(key.Pairing, h.pub, msg, sigs, h.group.Threshold, h.group.Len())
+	finalSig, err := h.group.Scheme.Recover(h.pub, msg, sigs, h.group.Threshold, h.group.Len())
 	if err != nil {
 		slog.Infof("beacon: could not reconstruct final beacon: %s", err)
 		return
 	}
-	if err := bls.Verify(key.Pairing, h.pub.Commit(), msg, finalSig); err != nil {
+	if err := h.group.Scheme.Verify(h.pub.Commit(), msg, finalSig); err != nil {
 		slog.Print(sigs)
 		slog.Print("beacon: invalid reconstructed beacon signature ? That's BAD, threshold ", h.group.Threshold)
 		return
@@ -338,26 +376,40 @@ func (h *Handler) run(round uint64, prevRand []byte, winCh chan roundInfo, close
 	//slog.Debugf("beacon: %s round %d -> saved beacon in store sucessfully", h.addr, round)
 	//slog.Infof("beacon: %s round %d finished: %x", h.addr, round, finalSig)
 	slog.Debugf("beacon: %s round %d finished: \n\tfinal: (id=%d) %x\n\tprev: %x\n", h.addr, round, beacon.Gid, finalSig, prevRand)
-	winCh <- roundInfo{round: round, signature: finalSig}
+	select {
+	case winCh <- roundInfo{round: round, signature: finalSig}:
+	case <-closeCh:
+	}
 }
 
-// Stop the beacon loop from aggregating  further randomness, but it
-// finishes the one it is aggregating currently.
+// Stop the beacon loop from aggregating further randomness. The current round
+// is abandoned if it is still waiting for partial signatures, and finished
+// otherwise. The store is closed once no round is running anymore.
 func (h *Handler) Stop() {
 	h.Lock()
-	defer h.Unlock()
 	if h.ticker != nil {
 		h.ticker.Stop()
 	}
 	close(h.close)
+	h.Unlock()
+	h.running.Wait()
 	h.store.Close()
 }
 
 // nextRound increase the round counter and evicts the cache from old entries.
+// If the rounds of the group are tied to time, the round jumps to the one due
+// now when the node is late.
 func (h *Handler) nextRound() uint64 {
 	h.Lock()
 	defer h.Unlock()
 	h.round++
+	if h.group.GenesisTime != 0 {
+		// the ticks may happen slightly before or after the round is due
+		due := h.group.RoundAt(time.Now().Add(-h.group.Period / 2))
+		if due > h.round {
+			h.round = due
+		}
+	}
 	h.cache.Evict(h.round)
 	return h.round
 }
@@ -384,7 +436,7 @@ func (h *Handler) signature(round uint64, msg []byte) ([]byte, error) {
 	var err error
 	signature, ok := h.cache.Get(round, msg)
 	if !ok {
-		signature, err = tbls.Sign(key.Pairing, h.share.Share, msg)
+		signature, err = h.group.Scheme.PartialSign(h.share.Share, msg)
 		if err != nil {
 			return nil, err
 		}
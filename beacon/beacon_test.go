@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	bolt "github.com/coreos/bbolt"
 	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
 	"github.com/dedis/drand/protobuf/crypto"
@@ -131,7 +132,7 @@ func TestBeacon(t *testing.T) {
 	require.True(t, exists)
 	launchBeacon := func(i int, catchup bool) {
 		myCb := func(b *Beacon) {
-			err := bls.Verify(key.Pairing, public, Message(b.PreviousRand, b.Round), b.Randomness)
+			err := group.Scheme.Verify(public, Message(group, b.PreviousRand, b.Round), b.Randomness)
 			require.NoError(t, err)
 			require.Equal(t, b.Gid, gid)
 			l.Lock()
@@ -248,6 +249,54 @@ func TestBeacon(t *testing.T) {
 	checkSuccess()
 }
 
+// TestBeaconGenesis checks that the rounds of a group with a genesis time are
+// produced when they are due.
+func TestBeaconGenesis(t *testing.T) {
+	n := 4
+	thr := 3
+	period := time.Second
+	shares, public := dkgShares(n, thr)
+	privs, group := test.BatchIdentities(n)
+	group.Threshold = thr
+	group.Period = period
+	group.GenesisTime = time.Now().Add(-10 * time.Second).Unix()
+	seed := []byte("Sunshine in a bottle")
+
+	tmp := path.Join(os.TempDir(), "drandtest-genesis")
+	defer os.RemoveAll(tmp)
+	produced := make(chan *Beacon, n)
+	for i := 0; i < n; i++ {
+		folder := path.Join(tmp, fmt.Sprintf("drand-%d", i))
+		require.NoError(t, os.MkdirAll(folder, 0755))
+		store, err := NewBoltStore(folder, nil)
+		require.NoError(t, err)
+		store = NewCallbackStore(store, func(b *Beacon) {
+			select {
+			case produced <- b:
+			default:
+			}
+		})
+		conf := &Config{Group: group, Private: privs[i], Share: shares[i], Seed: seed}
+		handler, err := NewHandler(net.NewGrpcClientWithTimeout(200*time.Millisecond), store, conf)
+		require.NoError(t, err)
+		listener := net.NewTCPGrpcListener(privs[i].Public.Addr, &net.DefaultService{B: &testBeaconServer{h: handler}})
+		go listener.Start()
+		defer listener.Stop()
+		defer handler.Stop()
+		go handler.Run(period, false)
+	}
+
+	for i := 0; i < 2*n; i++ {
+		b := <-produced
+		now := time.Now()
+		require.NoError(t, group.Scheme.Verify(public, Message(group, b.PreviousRand, b.Round), b.Randomness))
+		require.True(t, b.Round > 10)
+		due := group.TimeOfRound(b.Round)
+		require.False(t, now.Before(due), "round %d produced before it is due", b.Round)
+		require.True(t, now.Sub(due) < period, "round %d produced after the next one is due", b.Round)
+	}
+}
+
 func TestBeaconNEqualT(t *testing.T) {
 	slog.Level = slog.LevelDebug
 	n := 5
@@ -272,6 +321,9 @@ func TestBeaconNEqualT(t *testing.T) {
 	shares, public := dkgShares(n, thr)
 	privs, group := test.BatchIdentities(n)
 	group.Threshold = thr
+	// run with the standard hash to curve scheme and unchained beacons
+	group.Scheme = key.SchemeFromNameOrPanic(key.SchemeRFC9380BN254, key.KeyGroupG2)
+	group.Unchained = true
 
 	listeners := make([]net.Listener, n)
 	handlers := make([]*Handler, n)
@@ -290,7 +342,7 @@ func TestBeaconNEqualT(t *testing.T) {
 	// into the map
 	launchBeacon := func(i int, catchup bool) {
 		myCb := func(b *Beacon) {
-			err := bls.Verify(key.Pairing, public, Message(b.PreviousRand, b.Round), b.Randomness)
+			err := group.Scheme.Verify(public, Message(group, b.PreviousRand, b.Round), b.Randomness)
 			require.NoError(t, err)
 			l.Lock()
 			genBeacons[b.Round] = append(genBeacons[b.Round], b)
@@ -397,3 +449,43 @@ func TestBeaconNEqualT(t *testing.T) {
 
 	checkSuccess()
 }
+
+func TestBeaconStop(t *testing.T) {
+	n := 3
+	thr := 2
+	dir := path.Join(os.TempDir(), "drandtest-stop")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	defer os.RemoveAll(dir)
+
+	shares, _ := dkgShares(n, thr)
+	privs, group := test.BatchIdentities(n)
+	group.Threshold = thr
+	period := 100 * time.Millisecond
+	group.Period = period
+
+	store, err := NewBoltStore(dir, nil)
+	require.NoError(t, err)
+	conf := &Config{Group: group, Private: privs[0], Share: shares[0], Seed: []byte("seed")}
+	handler, err := NewHandler(net.NewGrpcClientWithTimeout(50*time.Millisecond), store, conf)
+	require.NoError(t, err)
+	go handler.Run(period, false)
+
+	// the other nodes are down so the current round waits for partial
+	// signatures and must be abandoned
+	time.Sleep(250 * time.Millisecond)
+	stopped := make(chan bool)
+	go func() {
+		handler.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("beacon handler not stopped")
+	}
+
+	// the database is closed so it can be opened again
+	store, err = NewBoltStore(dir, &bolt.Options{Timeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	store.Close()
+}
@@ -7,8 +7,10 @@ import (
 	"errors"
 	"path"
 	"sync"
+	"time"
 
 	bolt "github.com/coreos/bbolt"
+	"github.com/dedis/drand/key"
 	"github.com/nikkolasg/slog"
 )
 
@@ -30,11 +32,16 @@ type Beacon struct {
 }
 
 // Message returns a slice of bytes as the message to sign or to verify
-// alongside a beacon signature.
-func Message(prevRand []byte, round uint64) []byte {
+// alongside a beacon signature. By default, the message is the round number
+// followed by the previous randomness, chaining the beacons together. If the
+// group runs in unchained mode, the message is only the round number so each
+// beacon can be verified independently.
+func Message(g *key.Group, prevRand []byte, round uint64) []byte {
 	var buff bytes.Buffer
 	buff.Write(roundToBytes(round))
-	buff.Write(prevRand)
+	if !g.Unchained {
+		buff.Write(prevRand)
+	}
 	return buff.Bytes()
 }
 
@@ -157,6 +164,30 @@ func (b *boltStore) Get(round uint64) (*Beacon, error) {
 	return beacon, err
 }
 
+// ErrDBInUse is returned by BoltSnapshot when the database is opened by a
+// running daemon.
+var ErrDBInUse = errors.New("beacon database in use, stop the daemon first")
+
+// BoltSnapshot returns a consistent copy of the bolt database in the given
+// folder. The database is opened read-only, which fails with ErrDBInUse while
+// a daemon holds it.
+func BoltSnapshot(folder string) ([]byte, error) {
+	opts := &bolt.Options{ReadOnly: true, Timeout: time.Second}
+	db, err := bolt.Open(path.Join(folder, BoltFileName), 0660, opts)
+	if err == bolt.ErrTimeout {
+		return nil, ErrDBInUse
+	} else if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var buff bytes.Buffer
+	err = db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(&buff)
+		return err
+	})
+	return buff.Bytes(), err
+}
+
 type cbStore struct {
 	Store
 	cb func(*Beacon)
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dedis/drand/key"
 	"github.com/stretchr/testify/require"
 )
 
@@ -61,3 +62,13 @@ func TestBoltStore(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestBeaconMessage(t *testing.T) {
+	prev := []byte{0x01, 0x02, 0x03}
+	chained := Message(&key.Group{}, prev, 145)
+	require.Equal(t, append(roundToBytes(145), prev...), chained)
+
+	unchained := Message(&key.Group{Unchained: true}, prev, 145)
+	require.Equal(t, roundToBytes(145), unchained)
+	require.Equal(t, unchained, Message(&key.Group{Unchained: true}, nil, 145))
+}
@@ -3,10 +3,11 @@ package main
 import (
 	"io/ioutil"
 
+	"github.com/BurntSushi/toml"
 	"github.com/dedis/drand/core"
 	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
-	json "github.com/nikkolasg/hexjson"
+	"github.com/dedis/drand/protobuf/control"
 	"github.com/nikkolasg/slog"
 	"github.com/urfave/cli"
 )
@@ -15,7 +16,7 @@ import (
 // dispatch to the respective sub-commands.
 func shareCmd(c *cli.Context) error {
 	if !c.Args().Present() {
-		slog.Fatal("drand: needs at least one group.toml file argument")
+		fatalUsage("needs at least one group.toml file argument")
 	}
 	testEmptyGroup(c.Args().First())
 
@@ -26,14 +27,12 @@ func shareCmd(c *cli.Context) error {
 	}
 
 	conf := contextToConfig(c)
-	fs := key.NewFileStore(conf.ConfigFolder())
-	_, errG := fs.LoadGroup()
-	_, errS := fs.LoadShare()
-	_, errD := fs.LoadDistPublic()
-	// XXX place that logic inside core/ directly with only one method
-	freshRun := errG != nil || errS != nil || errD != nil
-	var err error
-	if freshRun {
+	state, err := core.StoredState(keyStore(c, conf.ConfigFolder()))
+	checkUnlocked(err)
+	if err != nil {
+		slog.Fatalf("drand: can't read the state of the node: %s", err)
+	}
+	if state == core.Fresh {
 		slog.Info("drand: no current distributed key -> running DKG protocol.")
 		err = initDKG(c)
 	} else {
@@ -56,7 +55,7 @@ func initDKG(c *cli.Context) error {
 		slog.Fatalf("drand: error creating control client: %s", err)
 	}
 
-	slog.Print("drand: waiting the end of DKG protocol ... " +
+	printInfo("drand: waiting the end of DKG protocol ... " +
 		"(you can CTRL-C to not quit waiting)")
 	_, err = client.InitDKG(groupPath, c.Bool(leaderFlag.Name), c.String(timeoutFlag.Name))
 	if err != nil {
@@ -82,12 +81,12 @@ func initReshare(c *cli.Context) error {
 		oldGroupPath = c.String(oldGroupFlag.Name)
 	}
 	if oldGroupPath == "" {
-		slog.Print("drand: old group path not specified. Using daemon's own group if possible.")
+		printInfo("drand: old group path not specified. Using daemon's own group if possible.")
 	}
 	newGroupPath = c.Args().First()
 
 	client := controlClient(c)
-	slog.Print("drand: initiating resharing protocol. Waiting to the end ...")
+	printInfo("drand: initiating resharing protocol. Waiting to the end ...")
 	_, err := client.InitReshare(oldGroupPath, newGroupPath, isLeader, c.String(timeoutFlag.Name))
 	if err != nil {
 		slog.Fatalf("drand: error resharing: %s", err)
@@ -101,7 +100,7 @@ func getShare(c *cli.Context) error {
 	if err != nil {
 		slog.Fatalf("drand: could not request the share: %s", err)
 	}
-	printJSON(resp)
+	printData(c.App.Writer, c, resp)
 	return nil
 }
 
@@ -110,15 +109,21 @@ func pingpongCmd(c *cli.Context) error {
 	if err := client.Ping(); err != nil {
 		slog.Fatalf("drand: can't ping the daemon ... %s", err)
 	}
-	slog.Printf("drand daemon is alive on port %s", controlPort(c))
+	printInfo("drand daemon is alive on port %s", controlPort(c))
 	return nil
 }
 
 func showGroupCmd(c *cli.Context) error {
 	client := controlClient(c)
-	r, err := client.Group()
+	var r *control.GroupResponse
+	var err error
+	if c.IsSet(epochFlag.Name) {
+		r, err = client.GroupAt(c.Int(epochFlag.Name))
+	} else {
+		r, err = client.Group()
+	}
 	if err != nil {
-		slog.Fatalf("drand: error asking for group file")
+		slog.Fatalf("drand: error asking for group file: %s", err)
 	}
 	if c.IsSet(outFlag.Name) {
 		filePath := c.String(outFlag.Name)
@@ -126,10 +131,18 @@ func showGroupCmd(c *cli.Context) error {
 		if err != nil {
 			slog.Fatalf("drand: can't write to file: %s", err)
 		}
-		slog.Printf("group file written to %s", filePath)
-	} else {
-		slog.Printf("\n\n%s", r.GroupToml)
+		printInfo("group file written to %s", filePath)
+		return nil
+	}
+	group := new(key.Group)
+	gtoml := group.TOMLValue()
+	if _, err := toml.Decode(r.GroupToml, gtoml); err != nil {
+		slog.Fatalf("drand: invalid group file: %s", err)
 	}
+	if err := group.FromTOML(gtoml); err != nil {
+		slog.Fatalf("drand: invalid group file: %s", err)
+	}
+	printData(c.App.Writer, c, group)
 	return nil
 }
 
@@ -139,7 +152,7 @@ func showCokeyCmd(c *cli.Context) error {
 	if err != nil {
 		slog.Fatalf("drand: could not request drand.cokey: %s", err)
 	}
-	printJSON(resp)
+	printData(c.App.Writer, c, resp)
 	return nil
 }
 
@@ -150,7 +163,7 @@ func showPrivateCmd(c *cli.Context) error {
 		slog.Fatalf("drand: could not request drand.private: %s", err)
 	}
 
-	printJSON(resp)
+	printData(c.App.Writer, c, resp)
 	return nil
 }
 
@@ -161,7 +174,7 @@ func showPublicCmd(c *cli.Context) error {
 		slog.Fatalf("drand: could not request drand.public: %s", err)
 	}
 
-	printJSON(resp)
+	printData(c.App.Writer, c, resp)
 	return nil
 }
 
@@ -172,7 +185,17 @@ func showShareCmd(c *cli.Context) error {
 		slog.Fatalf("drand: could not request drand.share: %s", err)
 	}
 
-	printJSON(resp)
+	printData(c.App.Writer, c, resp)
+	return nil
+}
+
+func showConnectionsCmd(c *cli.Context) error {
+	client := controlClient(c)
+	resp, err := client.Connections()
+	if err != nil {
+		slog.Fatalf("drand: could not request the connections: %s", err)
+	}
+	printData(c.App.Writer, c, resp)
 	return nil
 }
 
@@ -192,11 +215,3 @@ func controlClient(c *cli.Context) *net.ControlClient {
 	}
 	return client
 }
-
-func printJSON(j interface{}) {
-	buff, err := json.MarshalIndent(j, "", "    ")
-	if err != nil {
-		slog.Fatalf("drand: could not JSON marshal: %s", err)
-	}
-	slog.Print(string(buff))
-}
@@ -2,15 +2,18 @@ package core
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/dedis/drand/beacon"
+	"github.com/dedis/drand/derive"
 	"github.com/dedis/drand/ecies"
 	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
 	"github.com/dedis/drand/protobuf/crypto"
 	"github.com/dedis/drand/protobuf/drand"
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/util/random"
 	"google.golang.org/grpc"
 )
 
@@ -47,25 +50,71 @@ func NewRESTClientFromCert(c *net.CertManager) *Client {
 }
 
 // LastPublic returns the last randomness beacon from the server associated. It
-// returns it if the randomness is valid. Secure indicates that the request
-// must be made over a TLS protected channel.
-func (c *Client) LastPublic(addr string, pub *key.DistPublic, secure bool) (*drand.PublicRandResponse, error) {
+// returns it if the randomness is valid with respect to the group's
+// distributed key and beacon mode. Secure indicates that the request must be
+// made over a TLS protected channel.
+func (c *Client) LastPublic(addr string, group *key.Group, secure bool) (*drand.PublicRandResponse, error) {
 	resp, err := c.client.Public(&peerAddr{addr, secure}, &drand.PublicRandRequest{})
 	if err != nil {
 		return nil, err
 	}
-	return resp, c.verify(pub.Key(), resp)
+	return resp, c.verify(group, resp)
 }
 
 // Public returns the random output of the specified beacon at a given index. It
-// returns it if the randomness is valid. Secure indicates that the request
-// must be made over a TLS protected channel.
-func (c *Client) Public(addr string, pub *key.DistPublic, secure bool, round int) (*drand.PublicRandResponse, error) {
+// returns it if the randomness is valid with respect to the group's
+// distributed key and beacon mode. Secure indicates that the request must be
+// made over a TLS protected channel.
+func (c *Client) Public(addr string, group *key.Group, secure bool, round int) (*drand.PublicRandResponse, error) {
 	resp, err := c.client.Public(&peerAddr{addr, secure}, &drand.PublicRandRequest{Round: uint64(round)})
 	if err != nil {
 		return nil, err
 	}
-	return resp, c.verify(pub.Key(), resp)
+	return resp, c.verify(group, resp)
+}
+
+// PublicAt returns the random output of the first round due at or after the
+// given time, as computed from the genesis time and period of the group. It
+// returns it if it is the output of that round and the randomness is valid
+// with respect to the group's distributed key and beacon mode. Secure
+// indicates that the request must be made over a TLS protected channel.
+func (c *Client) PublicAt(addr string, group *key.Group, secure bool, t time.Time) (*drand.PublicRandResponse, error) {
+	if group.GenesisTime == 0 {
+		return nil, errors.New("drand: the rounds of the group are not tied to time")
+	}
+	// the time is sent in seconds
+	round := group.RoundAt(time.Unix(t.Unix(), 0))
+	resp, err := c.client.Public(&peerAddr{addr, secure}, &drand.PublicRandRequest{Time: t.Unix()})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetRound() != round {
+		return nil, fmt.Errorf("drand: got round %d instead of round %d due at the requested time", resp.GetRound(), round)
+	}
+	return resp, c.verify(group, resp)
+}
+
+// Randomness returns the 32-byte randomness derived from a public randomness
+// response, as defined in the derive package. The response must have been
+// verified, as done by Public and LastPublic.
+func (c *Client) Randomness(resp *drand.PublicRandResponse) ([]byte, error) {
+	rand := resp.GetRandomness()
+	if rand == nil {
+		return nil, errors.New("drand: no randomness found")
+	}
+	return derive.Randomness(rand.GetPoint()), nil
+}
+
+// Source returns a deterministic source of random values derived from a
+// public randomness response for the given context. It can be used to draw
+// integers in a range, permutations or weighted samples. The response must
+// have been verified, as done by Public and LastPublic.
+func (c *Client) Source(resp *drand.PublicRandResponse, context string) (*derive.Source, error) {
+	r, err := c.Randomness(resp)
+	if err != nil {
+		return nil, err
+	}
+	return derive.NewSource(r, context), nil
 }
 
 // Private retrieves a private random value from the server. It does that by
@@ -73,13 +122,18 @@ func (c *Client) Public(addr string, pub *key.DistPublic, secure bool, round int
 // and decrypts the response, the randomness. Client will attempt a TLS
 // connection to the address in the identity if id.IsTLS() returns true
 func (c *Client) Private(id *key.Identity) ([]byte, error) {
-	ephScalar := key.G2.Scalar()
-	ephPoint := key.G2.Point().Mul(ephScalar, nil)
+	groupable, ok := id.Key.(kyber.Groupable)
+	if !ok {
+		return nil, errors.New("drand: identity key is not on a registered curve")
+	}
+	g := groupable.Group()
+	ephScalar := g.Scalar().Pick(random.New())
+	ephPoint := g.Point().Mul(ephScalar, nil)
 	ephBuff, err := ephPoint.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	obj, err := ecies.Encrypt(key.G2, ecies.DefaultHash, id.Key, ephBuff)
+	obj, err := ecies.Encrypt(g, ecies.DefaultHash, id.Key, ephBuff)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +141,7 @@ func (c *Client) Private(id *key.Identity) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ecies.Decrypt(key.G2, ecies.DefaultHash, ephScalar, resp.GetResponse())
+	return ecies.Decrypt(g, ecies.DefaultHash, ephScalar, resp.GetResponse())
 }
 
 // DistKey returns the distributed key the node at this address is holding.
@@ -96,13 +150,43 @@ func (c *Client) DistKey(addr string, secure bool) (*crypto.Point, error) {
 	return resp.Key, err
 }
 
-func (c *Client) verify(public kyber.Point, resp *drand.PublicRandResponse) error {
-	msg := beacon.Message(resp.GetPrevious(), resp.GetRound())
+// DistKeyAt returns the distributed key that produced the given round, as kept
+// in the history of the node at this address.
+func (c *Client) DistKeyAt(addr string, secure bool, round uint64) (*crypto.Point, error) {
+	resp, err := c.client.DistKey(&peerAddr{addr, secure}, &drand.DistKeyRequest{Round: round})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Key, nil
+}
+
+// Group returns the current group of the node at this address. The group is
+// checked as a group file is, and against its chain hash, so it can be used
+// to verify the beacons of the node.
+func (c *Client) Group(addr string, secure bool) (*key.Group, error) {
+	resp, err := c.client.Group(&peerAddr{addr, secure}, &drand.GroupRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return groupFromResponse(resp)
+}
+
+func (c *Client) verify(group *key.Group, resp *drand.PublicRandResponse) error {
+	if group.PublicKey == nil {
+		return errors.New("drand: group has no distributed public key")
+	}
+	if group.Scheme == nil {
+		return errors.New("drand: group has no signature scheme")
+	}
+	msg := beacon.Message(group, resp.GetPrevious(), resp.GetRound())
 	rand := resp.GetRandomness()
 	if rand == nil {
 		return errors.New("drand: no randomness found")
 	}
-	return bls.Verify(key.Pairing, public, msg, rand.GetPoint())
+	if gid, _ := crypto.GroupToID(group.Scheme.SigGroup); int32(rand.GetGid()) != gid {
+		return errors.New("drand: randomness is not on the signature group of the scheme")
+	}
+	return group.Scheme.Verify(group.PublicKey.Key(), msg, rand.GetPoint())
 }
 
 func (c *Client) peer(addr string) {
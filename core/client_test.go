@@ -3,7 +3,13 @@ package core
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/dedis/drand/derive"
+	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/protobuf/crypto"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/dedis/drand/test"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,3 +31,59 @@ func TestClientPrivate(t *testing.T) {
 	require.NotNil(t, buff)
 	require.Len(t, buff, 32)
 }
+
+func TestClientRandomness(t *testing.T) {
+	client := NewGrpcClient()
+	_, err := client.Randomness(&drand.PublicRandResponse{Round: 1})
+	require.Error(t, err)
+
+	sig := []byte{0x01, 0x02, 0x03}
+	resp := &drand.PublicRandResponse{
+		Round:      1,
+		Randomness: &crypto.Point{Point: sig},
+	}
+	rand, err := client.Randomness(resp)
+	require.NoError(t, err)
+	require.Equal(t, derive.Randomness(sig), rand)
+
+	source, err := client.Source(resp, "lottery")
+	require.NoError(t, err)
+	exp := derive.NewSource(rand, "lottery").Permutation(10)
+	require.Equal(t, exp, source.Permutation(10))
+}
+
+func TestClientGroupResponse(t *testing.T) {
+	privs, group := test.BatchIdentities(4)
+	group.Period = 30 * time.Second
+	group.Scheme = key.DefaultScheme
+	for _, p := range privs[:2] {
+		p.Public.Name = "node"
+		require.NoError(t, p.SelfSign())
+	}
+	resp, err := groupResponse(group)
+	require.NoError(t, err)
+	require.Len(t, resp.GetNodes(), 4)
+
+	served, err := groupFromResponse(resp)
+	require.NoError(t, err)
+	require.Equal(t, group.Threshold, served.Threshold)
+	require.Equal(t, group.Period, served.Period)
+	require.True(t, served.PublicKey.Equal(group.PublicKey))
+	require.True(t, served.Nodes[0].IsSigned())
+	require.Equal(t, "node", served.Nodes[0].Name)
+	h1, err := group.Hash()
+	require.NoError(t, err)
+	h2, err := served.Hash()
+	require.NoError(t, err)
+	require.Equal(t, h1, h2)
+
+	// a group not matching its chain hash is rejected
+	resp.Period = "1m0s"
+	_, err = groupFromResponse(resp)
+	require.Error(t, err)
+	resp.Period = "30s"
+	// as well as a tampered signed identity
+	resp.Nodes[0].Address = "127.0.0.1:1"
+	_, err = groupFromResponse(resp)
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 // ConfigOption is a function that applies a specific setting to a Config.
@@ -20,16 +21,29 @@ type Config struct {
 	configFolder string
 	dbFolder     string
 	listenAddr   string
-	controlPort  string
-	grpcOpts     []grpc.DialOption
-	callOpts     []grpc.CallOption
-	dkgTimeout   time.Duration
-	boltOpts     *bolt.Options
-	beaconCbs    []func(*beacon.Beacon)
-	insecure     bool
-	certPath     string
-	keyPath      string
-	certmanager  *net.CertManager
+	publicAddr   string
+	restDisabled bool
+	// TLS settings of the public listener, the node's ones if empty
+	publicInsecure bool
+	publicCertPath string
+	publicKeyPath  string
+	nodeAuth       bool
+	limits         map[string]net.Limit
+	controlPort    string
+	grpcOpts       []grpc.DialOption
+	keepalive      time.Duration
+	keepaliveWait  time.Duration
+	maxBackoff     time.Duration
+	callOpts       []grpc.CallOption
+	dkgTimeout     time.Duration
+	boltOpts       *bolt.Options
+	keepShares     bool
+	resetDB        bool
+	beaconCbs      []func(*beacon.Beacon)
+	insecure       bool
+	certPath       string
+	keyPath        string
+	certmanager    *net.CertManager
 }
 
 // NewConfig returns the config to pass to drand with the default options set
@@ -47,6 +61,7 @@ func NewConfig(opts ...ConfigOption) *Config {
 		dkgTimeout:  dkg.DefaultTimeout,
 		certmanager: net.NewCertManager(),
 		controlPort: DefaultControlPort,
+		maxBackoff:  DefaultReconnectBackoff,
 	}
 	d.dbFolder = path.Join(d.configFolder, DefaultDbFolder)
 	for i := range opts {
@@ -80,6 +95,99 @@ func (d *Config) ListenAddress(defaultAddr string) string {
 	return defaultAddr
 }
 
+// PublicListenAddress returns the address the public API is bound to, set
+// thanks to WithPublicListenAddress. If empty, the public API is served on the
+// same listener as the protocols between nodes.
+func (d *Config) PublicListenAddress() string {
+	return d.publicAddr
+}
+
+// listenerOptions returns the options configuring the services of the main
+// listener, the one bound to the listen address.
+func (d *Config) listenerOptions() []net.ListenerOption {
+	opts := d.keepaliveOptions()
+	if d.publicAddr != "" {
+		return append(opts, net.PrivateServicesOnly())
+	}
+	return append(opts, d.publicOptions()...)
+}
+
+// keepaliveOptions returns the options making the listeners accept the
+// keepalive pings of the peers using the same interval, even on idle
+// connections, and ping them in turn. Without them, gRPC closes the
+// connections of peers pinging more often than every five minutes. Peers
+// pinging more often than the interval are disconnected.
+func (d *Config) keepaliveOptions() []net.ListenerOption {
+	if d.keepalive <= 0 {
+		return nil
+	}
+	return []net.ListenerOption{net.WithServerOptions(
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             d.keepalive,
+			PermitWithoutStream: true,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    d.keepalive,
+			Timeout: d.keepaliveWait,
+		}),
+	)}
+}
+
+// publicOptions returns the options configuring the public API of the
+// listener serving it.
+func (d *Config) publicOptions() []net.ListenerOption {
+	var opts []net.ListenerOption
+	if d.restDisabled {
+		opts = append(opts, net.WithoutREST())
+	}
+	if len(d.limits) > 0 {
+		opts = append(opts, net.WithRateLimiter(net.NewRateLimiter(d.limits)))
+	}
+	return opts
+}
+
+// publicListener returns the listener serving the public API on its own
+// address, or nil if the public API is served by the main listener.
+func (d *Config) publicListener(s net.Service, extra ...net.ListenerOption) (net.Listener, error) {
+	if d.publicAddr == "" {
+		return nil, nil
+	}
+	return d.newPublicListener(d.publicAddr, s, extra...)
+}
+
+// newPublicListener returns a listener serving only the public API on the
+// given address, with the TLS settings of the public listener.
+func (d *Config) newPublicListener(addr string, s net.Service, extra ...net.ListenerOption) (net.Listener, error) {
+	opts := append([]net.ListenerOption{net.PublicServicesOnly()}, d.keepaliveOptions()...)
+	opts = append(opts, d.publicOptions()...)
+	opts = append(opts, extra...)
+	if d.insecure || d.publicInsecure {
+		return net.NewTCPGrpcListener(addr, s, opts...), nil
+	}
+	certPath, keyPath := d.certPath, d.keyPath
+	if d.publicCertPath != "" {
+		certPath, keyPath = d.publicCertPath, d.publicKeyPath
+	}
+	return net.NewTLSGrpcListener(addr, certPath, keyPath, s, opts...)
+}
+
+// dialOptions returns the options used to dial other nodes: the ones given
+// with WithGrpcOptions followed by the keepalive and reconnection settings.
+func (d *Config) dialOptions() []grpc.DialOption {
+	opts := append([]grpc.DialOption{}, d.grpcOpts...)
+	if d.keepalive > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                d.keepalive,
+			Timeout:             d.keepaliveWait,
+			PermitWithoutStream: true,
+		}))
+	}
+	if d.maxBackoff > 0 {
+		opts = append(opts, grpc.WithBackoffMaxDelay(d.maxBackoff))
+	}
+	return opts
+}
+
 // ControlPort returns the port used for control port communications
 // which can be the default one or the port setup thanks to WithControlPort
 func (d *Config) ControlPort() string {
@@ -100,6 +208,29 @@ func WithGrpcOptions(opts ...grpc.DialOption) ConfigOption {
 	}
 }
 
+// WithKeepalive makes drand ping the nodes it is connected to after the given
+// interval of inactivity, and close the connections whose ping is not answered
+// within the timeout, DefaultKeepaliveTimeout if zero. Dead links are then
+// detected before the next round instead of stalling it. The listeners accept
+// pings as often as the interval, so all the nodes should use the same one.
+func WithKeepalive(interval, timeout time.Duration) ConfigOption {
+	return func(d *Config) {
+		if timeout == 0 {
+			timeout = DefaultKeepaliveTimeout
+		}
+		d.keepalive = interval
+		d.keepaliveWait = timeout
+	}
+}
+
+// WithReconnectBackoff sets the longest time to wait between two attempts to
+// reconnect to a node, DefaultReconnectBackoff by default.
+func WithReconnectBackoff(max time.Duration) ConfigOption {
+	return func(d *Config) {
+		d.maxBackoff = max
+	}
+}
+
 // WithCallOption applies grpc options when drand calls a gRPC method.
 func WithCallOption(opts ...grpc.CallOption) ConfigOption {
 	return func(d *Config) {
@@ -121,6 +252,24 @@ func WithBoltOptions(opts *bolt.Options) ConfigOption {
 	}
 }
 
+// WithShareHistory keeps the share of the node of each epoch in the history of
+// the store, instead of only the current one. Old shares allow to sign again
+// the rounds of past epochs, but an attacker stealing a threshold of them from
+// the nodes of an old group can do so as well.
+func WithShareHistory() ConfigOption {
+	return func(d *Config) {
+		d.keepShares = true
+	}
+}
+
+// WithResetDB removes, when starting a fresh node with StartDrand, the beacon
+// database left by a previous distributed key instead of failing.
+func WithResetDB() ConfigOption {
+	return func(d *Config) {
+		d.resetDB = true
+	}
+}
+
 // WithDbFolder sets the path folder for the db file. This path is NOT relative
 // to the DrandFolder path if set.
 func WithDbFolder(folder string) ConfigOption {
@@ -183,6 +332,64 @@ func WithListenAddress(addr string) ConfigOption {
 	}
 }
 
+// WithPublicListenAddress specifies the address the public API, over gRPC and
+// REST, should bind to. The listen address then only serves the protocols
+// between drand nodes, so it can be kept off the internet. By default, all
+// services are served on the listen address.
+func WithPublicListenAddress(addr string) ConfigOption {
+	return func(d *Config) {
+		d.publicAddr = addr
+	}
+}
+
+// WithPublicTLS registers the certificate and private key paths used by the
+// public listener, if they differ from the ones given with WithTLS.
+func WithPublicTLS(certPath, keyPath string) ConfigOption {
+	return func(d *Config) {
+		d.publicCertPath = certPath
+		d.publicKeyPath = keyPath
+	}
+}
+
+// WithPublicInsecure lets the public listener accept non-encrypted
+// connections, for example behind a proxy terminating TLS, while the
+// protocols between nodes still use TLS.
+func WithPublicInsecure() ConfigOption {
+	return func(d *Config) {
+		d.publicInsecure = true
+	}
+}
+
+// WithRESTDisabled turns off the REST API. Only gRPC clients can then fetch
+// randomness from the node.
+func WithRESTDisabled() ConfigOption {
+	return func(d *Config) {
+		d.restDisabled = true
+	}
+}
+
+// WithRateLimit applies the given limits to the requests of an endpoint of the
+// public API, one of the net.Endpoint constants. Rejected requests get a
+// ResourceExhausted error over gRPC and a 429 status over REST.
+func WithRateLimit(endpoint string, l net.Limit) ConfigOption {
+	return func(d *Config) {
+		if d.limits == nil {
+			d.limits = make(map[string]net.Limit)
+		}
+		d.limits[endpoint] = l
+	}
+}
+
+// WithNodeAuth makes drand reject the requests of the beacon and DKG protocols
+// that are not signed by the long-term key of a node of its current group, or
+// of the groups of a DKG or resharing in progress. All nodes sign these
+// requests.
+func WithNodeAuth() ConfigOption {
+	return func(d *Config) {
+		d.nodeAuth = true
+	}
+}
+
 // WithControlPort specifies which port on localhost the ListenerControl should bind to.
 func WithControlPort(port string) ConfigOption {
 	return func(d *Config) {
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	gnet "net"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/net"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/dedis/drand/test"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConfigKeepalive(t *testing.T) {
+	interval := 100 * time.Millisecond
+	c := NewConfig(WithInsecure(), WithKeepalive(interval, time.Second))
+	addr := test.Addresses(1)[0]
+	l, err := c.newPublicListener(addr, &net.DefaultService{})
+	require.NoError(t, err)
+	go l.Start()
+	defer l.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.Dial(addr, append(c.dialOptions(), grpc.WithInsecure())...)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := drand.NewRandomnessClient(conn)
+	_, err = client.Public(context.Background(), &drand.PublicRandRequest{})
+	require.NoError(t, err)
+
+	// the connection stays idle for several ping intervals: the listener
+	// accepts the pings instead of closing the connection
+	ctx, cancel := context.WithTimeout(context.Background(), 20*interval)
+	defer cancel()
+	require.False(t, conn.WaitForStateChange(ctx, connectivity.Ready))
+	_, err = client.Public(context.Background(), &drand.PublicRandRequest{})
+	require.NoError(t, err)
+
+	// a peer pinging more often than the interval is disconnected, which
+	// the gRPC clients do not allow, so the frames are written by hand
+	raw, err := gnet.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer raw.Close()
+	_, err = raw.Write([]byte(http2.ClientPreface))
+	require.NoError(t, err)
+	fr := http2.NewFramer(raw, raw)
+	require.NoError(t, fr.WriteSettings())
+	var headers bytes.Buffer
+	enc := hpack.NewEncoder(&headers)
+	for _, f := range [][2]string{
+		{":method", "POST"},
+		{":scheme", "http"},
+		{":path", "/drand.Randomness/Public"},
+		{":authority", addr},
+		{"content-type", "application/grpc"},
+		{"te", "trailers"},
+	} {
+		require.NoError(t, enc.WriteField(hpack.HeaderField{Name: f[0], Value: f[1]}))
+	}
+	require.NoError(t, fr.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      1,
+		BlockFragment: headers.Bytes(),
+		EndHeaders:    true,
+	}))
+	for i := 0; i < 5; i++ {
+		require.NoError(t, fr.WritePing(false, [8]byte{byte(i)}))
+	}
+	require.NoError(t, raw.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		f, err := fr.ReadFrame()
+		require.NoError(t, err, "the connection was not closed for too many pings")
+		if goAway, ok := f.(*http2.GoAwayFrame); ok {
+			require.Equal(t, http2.ErrCodeEnhanceYourCalm, goAway.ErrCode)
+			return
+		}
+	}
+}
@@ -34,3 +34,20 @@ const DefaultDKGTimeout = "10s"
 
 // DefaultDialTimeout is the timeout given to gRPC when dialling a remote server
 var DefaultDialTimeout = 3 * time.Second
+
+// DefaultReconnectBackoff is the longest time gRPC waits between two attempts
+// to reconnect to another node.
+const DefaultReconnectBackoff = 5 * time.Second
+
+// DefaultKeepaliveTimeout is the time a node waits for the answer to a
+// keepalive ping before closing the connection.
+const DefaultKeepaliveTimeout = 10 * time.Second
+
+// DefaultTransitionOffset is the time left by default between the creation of
+// the group of a resharing and its transition time, for the operators to start
+// the resharing on all the nodes.
+const DefaultTransitionOffset = 5 * time.Minute
+
+// MaxRelayCatchUp is the maximum number of missed rounds a relay fetches at
+// each period, so a relay far behind the nodes catches up gradually.
+const MaxRelayCatchUp = 100
@@ -13,6 +13,7 @@ import (
 	"github.com/dedis/drand/net"
 	dkg_proto "github.com/dedis/drand/protobuf/dkg"
 	"github.com/nikkolasg/slog"
+	"google.golang.org/grpc"
 )
 
 // Drand is the main logic of the program. It reads the keys / group file, it
@@ -37,6 +38,8 @@ type Drand struct {
 	// dkg public key. Can be nil if dkg not finished yet.
 	pub     *key.DistPublic
 	dkgDone bool
+	// history of the node, see loadEpochs
+	epochs []*key.Epoch
 
 	// proposed next group hash for a resharing operation
 	nextGroupHash     string
@@ -44,9 +47,15 @@ type Drand struct {
 	nextConf          *dkg.Config
 	nextOldPresent    bool // true if we are in the old group
 	nextFirstReceived bool // false til receive 1st reshare packet
+	// group of the beacon still running after a resharing, until the
+	// transition to the new group
+	prevGroup *key.Group
 
 	// global state lock
 	state sync.Mutex
+	// closed once the node is stopped
+	exitCh   chan bool
+	stopOnce sync.Once
 }
 
 // NewDrand returns an drand struct. It assumes the private key pair
@@ -74,18 +83,39 @@ func initDrand(s key.Store, c *Config) (*Drand, error) {
 	// identity. If there is an option to set the address, it will override the
 	// default set here..
 	d := &Drand{
-		store: s,
-		priv:  priv,
-		opts:  c,
+		store:  s,
+		priv:   priv,
+		opts:   c,
+		exitCh: make(chan bool),
 	}
 
 	a := c.ListenAddress(priv.Public.Address())
 	p := c.ControlPort()
+	maxAge := net.WithLatestMaxAge(d.latestMaxAge)
+	svc := &service{d}
+	public, err := c.publicListener(svc, maxAge)
+	if err != nil {
+		return nil, err
+	}
+	lopts := append(c.listenerOptions(), maxAge)
+	if c.nodeAuth {
+		lopts = append(lopts, net.WithNodeAuth(d.verifyNode))
+	}
+	// internal requests are always authenticated, so nodes requiring it
+	// accept them
+	grpcOpts := append(c.dialOptions(), grpc.WithUnaryInterceptor(net.AuthClientInterceptor(priv.Public.Address(), d.authSign)))
 	if c.insecure {
-		d.gateway = net.NewGrpcGatewayInsecure(a, p, d, d, d.opts.grpcOpts...)
+		d.gateway = net.NewGrpcGatewayInsecure(a, p, svc, d, lopts, grpcOpts...)
 	} else {
-		d.gateway = net.NewGrpcGatewayFromCertManager(a, p, c.certPath, c.keyPath, c.certmanager, d, d, d.opts.grpcOpts...)
+		d.gateway = net.NewGrpcGatewayFromCertManager(a, p, c.certPath, c.keyPath, c.certmanager, svc, d, lopts, grpcOpts...)
 	}
+	// nodes declaring the HTTP transport in the group are contacted over
+	// HTTP/1.1, the others over gRPC
+	d.gateway.InternalClient = net.NewTransportClient(map[string]net.InternalClient{
+		net.TransportGRPC: d.gateway.InternalClient,
+		net.TransportHTTP: net.NewHTTPClient(c.certmanager, priv.Public.Address(), d.authSign),
+	})
+	d.gateway.PublicListener = public
 	d.gateway.StartAll()
 	return d, nil
 }
@@ -109,6 +139,17 @@ func LoadDrand(s key.Store, c *Config) (*Drand, error) {
 	if err != nil {
 		return nil, err
 	}
+	// nodes set up before the history was kept start it with their current
+	// group
+	epochs, err := s.LoadEpochs()
+	if err != nil {
+		return nil, err
+	}
+	if len(epochs) == 0 && d.group.PublicKey != nil {
+		if err := d.saveEpoch(1, 0); err != nil {
+			return nil, err
+		}
+	}
 	slog.Debugf("drand: loaded and serving at %s", d.priv.Public.Address())
 	return d, nil
 }
@@ -138,10 +179,11 @@ func (d *Drand) WaitDKG() error {
 	d.state.Unlock()
 
 	slog.Debugf("drand: waiting DKG to start & finish at %s", time.Now())
+	var share *key.Share
 	select {
-	case share := <-waitCh:
-		s := key.Share(share)
-		d.share = &s
+	case dkgShare := <-waitCh:
+		s := key.Share(dkgShare)
+		share = &s
 	case err := <-errCh:
 		return fmt.Errorf("drand: error from dkg: %v", err)
 	}
@@ -149,20 +191,86 @@ func (d *Drand) WaitDKG() error {
 	d.state.Lock()
 	defer d.state.Unlock()
 
-	d.store.SaveShare(d.share)
-	d.store.SaveDistPublic(d.share.Public())
+	// a resharing run with another key than the one of the node rotates its
+	// key: the store must hold the key of the share before the share itself
+	if d.nextConf.Key != d.priv {
+		if err := d.store.RotateKeyPair(); err != nil {
+			return fmt.Errorf("drand: can't save the next key pair as the current one: %s", err)
+		}
+		d.priv = d.nextConf.Key
+	}
+	if err := d.store.SaveShare(share); err != nil {
+		return fmt.Errorf("drand: can't save the share: %s", err)
+	}
+	if err := d.store.SaveDistPublic(share.Public()); err != nil {
+		return fmt.Errorf("drand: can't save the distributed key: %s", err)
+	}
+	d.share = share
 	d.group = d.dkg.QualifiedGroup()
-	// need to save the period before since dkg returns a *new* fresh group, it
-	// does not know about the period.
+	// need to save the period, genesis time, beacon mode and scheme before
+	// since dkg returns a *new* fresh group, it does not know about them.
 	d.group.Period = d.nextConf.NewNodes.Period
+	d.group.GenesisTime = d.nextConf.NewNodes.GenesisTime
+	d.group.TransitionTime = d.nextConf.NewNodes.TransitionTime
+	d.group.Unchained = d.nextConf.NewNodes.Unchained
+	d.group.Scheme = d.nextConf.NewNodes.Scheme
 	slog.Debugf("drand: DKG finished with %d node certified at %s\n", d.group.Len(), time.Now())
-	d.store.SaveGroup(d.group)
+	if err := d.store.SaveGroup(d.group); err != nil {
+		return fmt.Errorf("drand: can't save the group: %s", err)
+	}
+	if err := d.nextEpoch(); err != nil {
+		slog.Infof("drand: can't save the epoch in the history: %s", err)
+	}
 	d.dkgDone = true
 	d.dkg = nil
 	d.nextConf = nil
 	return nil
 }
 
+// nextEpoch adds the current group to the history of the store, as the epoch
+// starting at its first round, the same on all the nodes. The groups without a
+// genesis time have no agreed first round: their epoch starts after the last
+// beacon produced by the node. It must be called with the state lock held.
+func (d *Drand) nextEpoch() error {
+	epochs, err := d.loadEpochs()
+	if err != nil {
+		return err
+	}
+	var from uint64
+	if d.group.GenesisTime != 0 {
+		from = d.group.FirstRound()
+	} else if d.beaconStore != nil {
+		if b, err := d.beaconStore.Last(); err == nil {
+			from = b.Round + 1
+		}
+	}
+	return d.saveEpoch(len(epochs)+1, from)
+}
+
+func (d *Drand) saveEpoch(n int, from uint64) error {
+	e := &key.Epoch{Number: n, FromRound: from, Group: d.group}
+	if d.opts.keepShares {
+		e.Share = d.share
+	}
+	// the history is loaded again on next use
+	d.epochs = nil
+	return d.store.SaveEpoch(e)
+}
+
+// loadEpochs returns the history of the node, loaded from the store on first
+// use. It must be called with the state lock held.
+func (d *Drand) loadEpochs() ([]*key.Epoch, error) {
+	if d.epochs != nil {
+		return d.epochs, nil
+	}
+	epochs, err := d.store.LoadEpochs()
+	if err != nil {
+		return nil, err
+	}
+	d.epochs = epochs
+	return epochs, nil
+}
+
 // createDKG create the new dkg handler according to the nextConf field. If the
 // dkg is not nil, it does not do anything.
 func (d *Drand) createDKG() error {
@@ -213,12 +321,83 @@ func (d *Drand) StopBeacon() {
 	d.beacon = nil
 }
 
-// Stop simply stops all drand operations.
+// Stop stops all drand operations gracefully: the listeners stop accepting
+// connections and drain the ones in progress, then the current round is
+// finished or abandoned and the beacon database is closed.
 func (d *Drand) Stop() {
-	d.StopBeacon()
+	d.stopOnce.Do(func() {
+		d.gateway.StopAll()
+		d.StopBeacon()
+		close(d.exitCh)
+	})
+}
+
+// WaitExit returns a channel closed once the node is stopped, by Stop or by
+// the Shutdown control command.
+func (d *Drand) WaitExit() chan bool {
+	return d.exitCh
+}
+
+// latestMaxAge returns how long the latest beacon can be cached by clients:
+// until the next round is due.
+func (d *Drand) latestMaxAge() time.Duration {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.group == nil || d.beaconStore == nil {
+		return 0
+	}
+	return untilNextRound(d.group, d.beaconStore)
+}
+
+// untilNextRound returns the time until the round following the last beacon of
+// the store is due, or 0 if the rounds of the group are not tied to time.
+func untilNextRound(group *key.Group, store beacon.Store) time.Duration {
+	if group.GenesisTime == 0 {
+		return 0
+	}
+	b, err := store.Last()
+	if err != nil {
+		return 0
+	}
+	return time.Until(group.TimeOfRound(b.Round + 1))
+}
+
+// authSign signs the requests to other nodes with the current long-term key,
+// which changes when the key is rotated.
+func (d *Drand) authSign(msg []byte) ([]byte, error) {
+	d.state.Lock()
+	priv := d.priv
+	d.state.Unlock()
+	return priv.AuthSign(msg)
+}
+
+// verifyNode checks that the node at the given address belongs to the current
+// group or to one of the groups of a DKG or resharing in progress, and that sig
+// is its signature of msg. A node rotating its key is listed at the same
+// address with its old key in the old group and its new key in the new one, so
+// all the keys at the address are tried.
+func (d *Drand) verifyNode(addr string, msg, sig []byte) error {
 	d.state.Lock()
-	d.gateway.StopAll()
+	groups := []*key.Group{d.group, d.prevGroup}
+	if d.nextConf != nil {
+		groups = append(groups, d.nextConf.OldNodes, d.nextConf.NewNodes)
+	}
 	d.state.Unlock()
+	err := errors.New("node not part of any group")
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, id := range g.Identities() {
+			if id.Addr != addr {
+				continue
+			}
+			if err = id.AuthVerify(msg, sig); err == nil {
+				return nil
+			}
+		}
+	}
+	return err
 }
 
 // isDKGDone returns true if the DKG protocol has already been executed. That
@@ -20,6 +20,11 @@ import (
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
 )
 
+// errExternalKey is returned when a DKG or resharing is requested while the
+// long-term key is held by an external signer: the DKG library uses the private
+// key itself.
+var errExternalKey = errors.New("drand: the DKG and resharing need the long-term private key, which is held by an external signer: restart the node with its private key file to run them")
+
 // InitDKG take a DKGRequest, extracts the informations needed and wait for the
 // DKG protocol to finish. If the request specifies this node is a leader, it
 // starts the DKG protocol.
@@ -30,6 +35,10 @@ func (d *Drand) InitDKG(c context.Context, in *control.DKGRequest) (*control.DKG
 		d.state.Unlock()
 		return nil, errors.New("drand: dkg phase already done. Can't run 2 init DKG")
 	}
+	if d.priv.Key == nil {
+		d.state.Unlock()
+		return nil, errExternalKey
+	}
 
 	group, err := extractGroup(in.GetDkgGroup())
 	if err != nil {
@@ -45,7 +54,7 @@ func (d *Drand) InitDKG(c context.Context, in *control.DKGRequest) (*control.DKG
 	d.idx = idx
 
 	d.nextConf = &dkg.Config{
-		Suite:    key.G2.(dkg.Suite),
+		Suite:    d.group.Scheme.KeyGroup.(dkg.Suite),
 		NewNodes: d.group,
 		Key:      d.priv,
 	}
@@ -77,11 +86,22 @@ func (d *Drand) InitDKG(c context.Context, in *control.DKGRequest) (*control.DKG
 // received node is stated as a leader and is present in the old group.
 // This function waits for the resharing DKG protocol to finish.
 func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*control.ReshareResponse, error) {
-	var oldGroup, newGroup *key.Group
-	var err error
+	d.state.Lock()
+	priv := d.priv
+	d.state.Unlock()
+	oldGroup, newGroup, oldPresent, err := d.reshare(in, priv)
+	if err != nil {
+		return nil, err
+	}
+	return &control.ReshareResponse{}, d.switchGroup(oldGroup, newGroup, !oldPresent)
+}
 
+// reshare runs the resharing protocol as the node holding the given long-term
+// key and waits for it to finish. It returns the old and new groups and
+// whether the key is part of the old group.
+func (d *Drand) reshare(in *control.ReshareRequest, priv *key.Pair) (oldGroup, newGroup *key.Group, oldPresent bool, err error) {
 	if newGroup, err = extractGroup(in.New); err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
 
 	d.state.Lock()
@@ -89,18 +109,34 @@ func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*con
 		// try to get the current group
 		if d.group == nil {
 			d.state.Unlock()
-			return nil, errors.New("drand: can't init-reshare if no old group provided")
+			return nil, nil, false, errors.New("drand: can't init-reshare if no old group provided")
 		}
 		slog.Debugf("drand: using current group as old group in resharing request")
 		oldGroup = d.group
 	}
 	d.state.Unlock()
+	// the round due at a given time must not change with the group
+	if newGroup.GenesisTime != oldGroup.GenesisTime || (newGroup.GenesisTime != 0 && newGroup.Period != oldGroup.Period) {
+		return nil, nil, false, errors.New("drand: the new group must keep the genesis time and period of the old group")
+	}
+	// all the nodes switch to the new group at the same round
+	if newGroup.GenesisTime != 0 {
+		if newGroup.TransitionTime == 0 {
+			return nil, nil, false, errors.New("drand: the new group has no transition time")
+		}
+		if !time.Now().Before(transitionSwitch(newGroup)) {
+			return nil, nil, false, errors.New("drand: the transition time of the new group has passed, create it again with a later one")
+		}
+	}
 
-	oldIdx, oldPresent := oldGroup.Index(d.priv.Public)
+	oldIdx, oldPresent := oldGroup.Index(priv.Public)
 	err = func() error {
 		d.state.Lock()
 		defer d.state.Unlock()
 
+		if priv.Key == nil {
+			return errExternalKey
+		}
 		if oldPresent {
 			if d.group == nil {
 				return errors.New("control: present in old group but no dkg here")
@@ -124,8 +160,8 @@ func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*con
 		conf := &dkg.Config{
 			OldNodes: oldGroup,
 			NewNodes: newGroup,
-			Key:      d.priv,
-			Suite:    key.G2.(dkg.Suite),
+			Key:      priv,
+			Suite:    newGroup.Scheme.KeyGroup.(dkg.Suite),
 		}
 
 		// run the proto
@@ -156,7 +192,7 @@ func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*con
 	}()
 
 	if err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
 
 	if oldPresent && in.GetIsLeader() {
@@ -165,7 +201,28 @@ func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*con
 		d.startResharingAsLeader(oldIdx)
 	}
 	if err := d.WaitDKG(); err != nil {
-		return nil, err
+		return nil, nil, false, err
+	}
+	return oldGroup, newGroup, oldPresent, nil
+}
+
+// switchGroup restarts the beacon with the shares of the new group once a
+// resharing is done. The beacon of the old group keeps producing the rounds
+// until the first one of the new group, and its nodes are still accepted until
+// then.
+func (d *Drand) switchGroup(oldGroup, newGroup *key.Group, catchup bool) error {
+	if newGroup.GenesisTime != 0 {
+		d.state.Lock()
+		d.prevGroup = oldGroup
+		d.state.Unlock()
+		slog.Infof("drand: resharing done, new group taking over at round %d", newGroup.FirstRound())
+		err := d.waitUntil(transitionSwitch(newGroup))
+		d.state.Lock()
+		d.prevGroup = nil
+		d.state.Unlock()
+		if err != nil {
+			return err
+		}
 	}
 	// stop the beacon first, then re-create it with the new shares
 	// i.e. the current beacon is still running alongside with the
@@ -174,11 +231,27 @@ func (d *Drand) InitReshare(c context.Context, in *control.ReshareRequest) (*con
 	d.StopBeacon()
 	d.initBeacon()
 	time.Sleep(500 * time.Millisecond)
-	catchup := true
-	if oldPresent {
-		catchup = false
+	return d.StartBeacon(catchup)
+}
+
+// transitionSwitch returns the time at which the nodes switch to the group of
+// a resharing: half a period before its first round, so the beacon of the old
+// group has finished the previous round and the new one waits for the first.
+func transitionSwitch(g *key.Group) time.Time {
+	return g.TimeOfRound(g.FirstRound()).Add(-g.Period / 2)
+}
+
+// waitUntil waits until the given time, or returns an error if the node is
+// stopped before.
+func (d *Drand) waitUntil(t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-d.exitCh:
+		return errors.New("drand: node stopped")
 	}
-	return &control.ReshareResponse{}, d.StartBeacon(catchup)
 }
 
 func (d *Drand) startResharingAsLeader(oidx int) {
@@ -205,7 +278,10 @@ func (d *Drand) startResharingAsLeader(oidx int) {
 }
 
 // DistKey returns the distributed key corresponding to the current group
-func (d *Drand) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+func (d *Drand) DistKey(c context.Context, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	if in.GetRound() != 0 {
+		return d.distKeyAt(in.GetRound())
+	}
 	pt, err := d.store.LoadDistPublic()
 	if err != nil {
 		return nil, errors.New("drand: could not load dist. key")
@@ -219,6 +295,26 @@ func (d *Drand) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyR
 	}, nil
 }
 
+// distKeyAt returns the distributed key of the epoch that produced the round,
+// from the history of the node.
+func (d *Drand) distKeyAt(round uint64) (*drand.DistKeyResponse, error) {
+	d.state.Lock()
+	epochs, err := d.loadEpochs()
+	d.state.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("drand: could not load the history: %s", err)
+	}
+	e := key.EpochAt(epochs, round)
+	if e == nil {
+		return nil, fmt.Errorf("drand: no distributed key known for round %d", round)
+	}
+	pt, err := crypto.KyberToProtoPoint(e.DistPublic().Key())
+	if err != nil {
+		return nil, err
+	}
+	return &drand.DistKeyResponse{Key: pt}, nil
+}
+
 // PingPong simply responds with an empty packet, proving that this drand node
 // is up and alive.
 func (d *Drand) PingPong(c context.Context, in *control.Ping) (*control.Pong, error) {
@@ -262,6 +358,9 @@ func (d *Drand) PrivateKey(ctx context.Context, in *control.PrivateKeyRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if key.Key == nil {
+		return nil, errors.New("drand: the private key is held by an external signer")
+	}
 	protoKey, err := crypto.KyberToProtoScalar(key.Key)
 	if err != nil {
 		return nil, err
@@ -289,15 +388,102 @@ func (d *Drand) CollectiveKey(ctx context.Context, in *control.CokeyRequest) (*c
 func (d *Drand) Group(ctx context.Context, in *control.GroupRequest) (*control.GroupResponse, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
-	if d.group == nil {
+	group := d.group
+	if n := int(in.GetEpoch()); n != 0 {
+		epochs, err := d.loadEpochs()
+		if err != nil {
+			return nil, err
+		}
+		if n > len(epochs) {
+			return nil, fmt.Errorf("drand: no epoch %d, the history has %d epochs", n, len(epochs))
+		}
+		group = epochs[n-1].Group
+	}
+	if group == nil {
 		return nil, errors.New("drand: no dkg group setup yet")
 	}
-	gtoml := d.group.TOML()
+	gtoml := group.TOML()
 	var buff bytes.Buffer
 	err := toml.NewEncoder(&buff).Encode(gtoml)
 	return &control.GroupResponse{GroupToml: buff.String()}, err
 }
 
+// Connections replies with the status of the connections of this drand node
+// to the other nodes.
+func (d *Drand) Connections(ctx context.Context, in *control.ConnectionsRequest) (*control.ConnectionsResponse, error) {
+	resp := &control.ConnectionsResponse{}
+	for _, c := range d.gateway.InternalClient.Connections() {
+		status := &control.ConnectionStatus{
+			Address:    c.Address,
+			Tls:        c.TLS,
+			State:      c.State,
+			Failures:   uint32(c.Failures),
+			Reconnects: uint32(c.Reconnects),
+		}
+		if c.LastError != nil {
+			status.LastError = c.LastError.Error()
+		}
+		resp.Connections = append(resp.Connections, status)
+	}
+	return resp, nil
+}
+
+// Shutdown stops the drand node gracefully, see Stop. The node is stopped once
+// the reply is sent.
+func (d *Drand) Shutdown(ctx context.Context, in *control.ShutdownRequest) (*control.ShutdownResponse, error) {
+	slog.Infof("drand: shutdown requested")
+	go d.Stop()
+	return &control.ShutdownResponse{}, nil
+}
+
+// RotateKey replaces the long-term key of the node with the next key pair of
+// its store. The node takes part in the resharing towards the new group as a
+// new node holding the next key: its old share is not used, so a threshold of
+// the other nodes of the current group must take part. The node keeps using
+// its current key until the resharing succeeded: only then the next key
+// replaces it, in the store before the new share and then in the running node.
+func (d *Drand) RotateKey(c context.Context, in *control.RotateKeyRequest) (*control.RotateKeyResponse, error) {
+	next, err := d.store.LoadNextKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("drand: no next key pair to rotate to: %s", err)
+	}
+	newGroup, err := extractGroup(in.GetNew())
+	if err != nil {
+		return nil, err
+	}
+	d.state.Lock()
+	current := d.priv
+	if d.group == nil || !d.dkgDone {
+		d.state.Unlock()
+		return nil, errors.New("drand: can't rotate the key before the first DKG")
+	}
+	if _, found := newGroup.Index(next.Public); !found {
+		d.state.Unlock()
+		return nil, errors.New("drand: the new group does not hold the next key of the node")
+	}
+	if next.Public.Address() != current.Public.Address() {
+		d.state.Unlock()
+		return nil, errors.New("drand: the next key must keep the address of the node")
+	}
+	if newGroup.Contains(current.Public) {
+		d.state.Unlock()
+		return nil, errors.New("drand: the new group still holds the current key of the node")
+	}
+	d.state.Unlock()
+
+	slog.Infof("drand: rotating the long-term key by resharing to the new group")
+	// an empty old group is the current group
+	req := &control.ReshareRequest{Old: &control.GroupInfo{}, New: in.GetNew(), Timeout: in.GetTimeout()}
+	// the next key replaces the current one once the resharing is done, see
+	// WaitDKG
+	oldGroup, newGroup, _, err := d.reshare(req, next)
+	if err != nil {
+		return nil, fmt.Errorf("drand: resharing failed: %s", err)
+	}
+	slog.Infof("drand: long-term key rotated")
+	return &control.RotateKeyResponse{}, d.switchGroup(oldGroup, newGroup, true)
+}
+
 func extractGroup(i *control.GroupInfo) (*key.Group, error) {
 	var g = &key.Group{}
 	switch x := i.Location.(type) {
@@ -2,8 +2,10 @@ package core
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dedis/drand/beacon"
 	"github.com/dedis/drand/ecies"
@@ -76,32 +78,58 @@ func (d *Drand) NewBeacon(c context.Context, in *drand.BeaconRequest) (*drand.Be
 }
 
 // Public returns a public random beacon according to the request. If the Round
-// field is 0, then it returns the last one generated.
+// field is set, it returns the beacon of that round. If the Time field is set,
+// it returns the beacon of the first round due at or after that unix time.
+// Otherwise, it returns the last one generated.
 func (d *Drand) Public(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
 	if d.beacon == nil {
 		return nil, errors.New("drand: beacon generation not started yet")
 	}
-	var beacon *beacon.Beacon
+	return publicFromStore(d.group, d.beaconStore, in)
+}
+
+// publicFromStore answers a public randomness request from the beacons of the
+// group saved in the given store. The round of a time and the time of a round
+// are computed from the genesis time and period of the group, so all nodes
+// answer the same and clients can check it.
+func publicFromStore(group *key.Group, store beacon.Store, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	if in.GetRound() != 0 && in.GetTime() != 0 {
+		return nil, errors.New("drand: can't request a round and a time at the same time")
+	}
+	var b *beacon.Beacon
 	var err error
-	if in.GetRound() == 0 {
-		beacon, err = d.beaconStore.Last()
-	} else {
-		beacon, err = d.beaconStore.Get(in.GetRound())
+	switch {
+	case in.GetRound() != 0:
+		b, err = store.Get(in.GetRound())
+	case in.GetTime() != 0:
+		if group.GenesisTime == 0 {
+			return nil, errors.New("drand: the rounds of the group are not tied to time")
+		}
+		round := group.RoundAt(time.Unix(in.GetTime(), 0))
+		if b, err = store.Get(round); err != nil {
+			return nil, fmt.Errorf("can't retrieve beacon of round %d: %s", round, err)
+		}
+	default:
+		b, err = store.Last()
 	}
 	if err != nil {
 		return nil, fmt.Errorf("can't retrieve beacon: %s", err)
 	}
 
-	return &drand.PublicRandResponse{
-		Previous: beacon.PreviousRand,
-		Round:    beacon.Round,
+	resp := &drand.PublicRandResponse{
+		Previous: b.PreviousRand,
+		Round:    b.Round,
 		Randomness: &crypto.Point{
-			Point: beacon.Randomness,
-			Gid:   crypto.GroupID(beacon.Gid),
+			Point: b.Randomness,
+			Gid:   crypto.GroupID(b.Gid),
 		},
-	}, nil
+	}
+	if group.GenesisTime != 0 {
+		resp.Timestamp = group.TimeOfRound(b.Round).Unix()
+	}
+	return resp, nil
 }
 
 // Private returns an ECIES encrypted random blob of 32 bytes from /dev/urandom
@@ -115,16 +143,19 @@ func (d *Drand) Private(c context.Context, priv *drand.PrivateRandRequest) (*dra
 	if !ok {
 		return nil, errors.New("point is not on a registered curve")
 	}
-	if groupable.Group().String() != key.G2.String() {
+	// the request is encrypted to the long-term key of the node, which may be
+	// held by an external signer
+	keyGroup := d.priv.Public.Key.(kyber.Groupable).Group()
+	if groupable.Group().String() != keyGroup.String() {
 		return nil, errors.New("point is not on the supported curve")
 	}
-	msg, err := ecies.Decrypt(key.G2, ecies.DefaultHash, d.priv.Key, priv.GetRequest())
+	msg, err := ecies.DecryptWith(keyGroup, ecies.DefaultHash, d.priv.DH, priv.GetRequest())
 	if err != nil {
 		slog.Debugf("drand: received invalid ECIES private request: %s", err)
 		return nil, errors.New("invalid ECIES request")
 	}
 
-	clientKey := key.G2.Point()
+	clientKey := keyGroup.Point()
 	if err := clientKey.UnmarshalBinary(msg); err != nil {
 		return nil, errors.New("invalid client key")
 	}
@@ -135,10 +166,125 @@ func (d *Drand) Private(c context.Context, priv *drand.PrivateRandRequest) (*dra
 		return nil, fmt.Errorf("error gathering randomness: expected 32 bytes, got %d", len(randomness))
 	}
 
-	obj, err := ecies.Encrypt(key.G2, ecies.DefaultHash, clientKey, randomness[:])
+	obj, err := ecies.Encrypt(keyGroup, ecies.DefaultHash, clientKey, randomness[:])
 	return &drand.PrivateRandResponse{Response: obj}, err
 }
 
+// service is the drand node as served to the other nodes and to the clients.
+// It is needed since the Info service and the control service both have a
+// Group method.
+type service struct {
+	*Drand
+}
+
+// Group returns the current public group of the node.
+func (s *service) Group(c context.Context, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	s.state.Lock()
+	group := s.group
+	s.state.Unlock()
+	if group == nil {
+		return nil, errors.New("drand: no dkg group setup yet")
+	}
+	return groupResponse(group)
+}
+
+// groupResponse returns the public group, as served by nodes and relays.
+func groupResponse(group *key.Group) (*drand.GroupResponse, error) {
+	hash, err := group.ChainHash(DefaultSeed)
+	if err != nil {
+		return nil, err
+	}
+	gtoml := group.TOML().(*key.GroupTOML)
+	resp := &drand.GroupResponse{
+		Hash:        hash,
+		HashVersion: key.ChainHashVersion,
+		Threshold:   uint32(group.Threshold),
+		Period:      gtoml.Period,
+		GenesisTime: group.GenesisTime,
+		Unchained:   group.Unchained,
+		Scheme:      gtoml.Scheme,
+		KeyGroup:    gtoml.KeyGroup,
+	}
+	for _, id := range group.Nodes {
+		pt, err := crypto.KyberToProtoPoint(id.Key)
+		if err != nil {
+			return nil, err
+		}
+		resp.Nodes = append(resp.Nodes, &drand.Node{
+			Address:        id.Addr,
+			Key:            pt,
+			Tls:            id.TLS,
+			Transport:      id.Transport,
+			Name:           id.Name,
+			Contact:        id.Contact,
+			Region:         id.Region,
+			TlsFingerprint: id.TLSFingerprint,
+			Signature:      id.Signature,
+		})
+	}
+	for _, c := range group.PublicKey.Coefficients {
+		pt, err := crypto.KyberToProtoPoint(c)
+		if err != nil {
+			return nil, err
+		}
+		resp.DistKey = append(resp.DistKey, pt)
+	}
+	return resp, nil
+}
+
+// groupFromResponse returns the group served by a node, after checking it as
+// a group file is checked when loaded, and that it matches its chain hash.
+func groupFromResponse(resp *drand.GroupResponse) (*key.Group, error) {
+	gtoml := &key.GroupTOML{
+		Threshold:   int(resp.GetThreshold()),
+		Period:      resp.GetPeriod(),
+		GenesisTime: resp.GetGenesisTime(),
+		Unchained:   resp.GetUnchained(),
+		Scheme:      resp.GetScheme(),
+		KeyGroup:    resp.GetKeyGroup(),
+		PublicKey:   &key.DistPublicTOML{},
+	}
+	for _, n := range resp.GetNodes() {
+		pt, err := crypto.ProtoToKyberPoint(n.GetKey())
+		if err != nil {
+			return nil, fmt.Errorf("drand: invalid key of node %s: %s", n.GetAddress(), err)
+		}
+		gtoml.Nodes = append(gtoml.Nodes, &key.PublicTOML{
+			Address:        n.GetAddress(),
+			Key:            key.PointToString(pt),
+			TLS:            n.GetTls(),
+			Transport:      n.GetTransport(),
+			Name:           n.GetName(),
+			Contact:        n.GetContact(),
+			Region:         n.GetRegion(),
+			TLSFingerprint: n.GetTlsFingerprint(),
+			Signature:      hex.EncodeToString(n.GetSignature()),
+		})
+	}
+	for _, c := range resp.GetDistKey() {
+		pt, err := crypto.ProtoToKyberPoint(c)
+		if err != nil {
+			return nil, fmt.Errorf("drand: invalid distributed key: %s", err)
+		}
+		gtoml.PublicKey.Coefficients = append(gtoml.PublicKey.Coefficients, key.PointToString(pt))
+	}
+	group := new(key.Group)
+	if err := group.FromTOML(gtoml); err != nil {
+		return nil, err
+	}
+	if resp.GetHashVersion() != key.ChainHashVersion {
+		return nil, fmt.Errorf("drand: unsupported chain hash version %d", resp.GetHashVersion())
+	}
+	hash, err := group.ChainHash(DefaultSeed)
+	if err != nil {
+		return nil, err
+	}
+	if hash != resp.GetHash() {
+		return nil, errors.New("drand: the group does not match its chain hash")
+	}
+	return group, nil
+}
+
 // Home ...
 func (d *Drand) Home(c context.Context, in *drand.HomeRequest) (*drand.HomeResponse, error) {
 	slog.Infof("drand: home method requested")
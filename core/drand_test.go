@@ -21,7 +21,6 @@ import (
 	"github.com/kabukky/httpscerts"
 	"github.com/nikkolasg/slog"
 	"github.com/stretchr/testify/require"
-	"go.dedis.ch/kyber/v3/sign/bls"
 )
 
 func TestDrandDKGReshareTimeout(t *testing.T) {
@@ -208,6 +207,100 @@ func TestDrandDKGReshare(t *testing.T) {
 	//require.NoError(t, err)
 	wg.Wait()
 
+	// the resharing is recorded in the history and keeps the distributed key
+	epochs, err := root.store.LoadEpochs()
+	require.NoError(t, err)
+	require.Len(t, epochs, 1)
+	require.True(t, epochs[0].DistPublic().Key().Equal(dpub[0]))
+	require.Equal(t, newN, epochs[0].Group.Len())
+}
+
+func TestDrandRotateKey(t *testing.T) {
+	slog.Level = slog.LevelDebug
+
+	n := 5
+	thr := key.DefaultThreshold(n)
+	shares, dpub := test.SimulateDKG(t, key.G2, n, thr)
+	period := 1000 * time.Millisecond
+
+	drands, _, dir := BatchNewDrand(n, false,
+		WithCallOption(grpc.FailFast(true)))
+	defer CloseAllDrands(drands)
+	defer os.RemoveAll(dir)
+
+	ids := make([]*key.Identity, n)
+	for i, d := range drands {
+		ids[i] = d.priv.Public
+		drands[i].idx = i
+	}
+	oldGroup := key.LoadGroup(ids, &key.DistPublic{Coefficients: dpub}, thr)
+	oldGroup.Period = period
+	oldPath := path.Join(dir, "oldgroup.toml")
+	require.NoError(t, key.Save(oldPath, oldGroup, false))
+	for i, d := range drands {
+		d.group = oldGroup
+		d.dkgDone = true
+		d.share = &key.Share{Share: shares[i], Commits: dpub}
+	}
+
+	// the last node rotates its key, keeping its address
+	rotated := drands[n-1]
+	current := rotated.priv
+	next := key.NewTLSKeyPair(current.Public.Address())
+	require.NoError(t, rotated.store.SaveNextKeyPair(next))
+	newGroup := key.NewGroup(append(ids[:n-1:n-1], next.Public), thr)
+	newGroup.Period = period
+	newPath := path.Join(dir, "newgroup.toml")
+	require.NoError(t, key.Save(newPath, newGroup, false))
+
+	var wg sync.WaitGroup
+	wg.Add(n - 1)
+	for _, drand := range drands[1 : n-1] {
+		go func(d *Drand) {
+			client, err := net.NewControlClient(d.opts.controlPort)
+			require.NoError(t, err)
+			_, err = client.InitReshare(oldPath, newPath, false, "")
+			require.NoError(t, err)
+			wg.Done()
+		}(drand)
+	}
+	go func() {
+		client, err := net.NewControlClient(rotated.opts.controlPort)
+		require.NoError(t, err)
+		_, err = client.RotateKey(newPath, "")
+		require.NoError(t, err)
+		wg.Done()
+	}()
+
+	root := drands[0]
+	client, err := net.NewControlClient(root.opts.controlPort)
+	require.NoError(t, err)
+	_, err = client.InitReshare(oldPath, newPath, true, "")
+	require.NoError(t, err)
+	wg.Wait()
+
+	// the next key replaced the current one only once the resharing is done
+	rotated.state.Lock()
+	require.True(t, rotated.priv.Public.Key.Equal(next.Public.Key))
+	rotated.state.Unlock()
+	saved, err := rotated.store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, saved.Public.Key.Equal(next.Public.Key))
+	_, err = rotated.store.LoadNextKeyPair()
+	require.Error(t, err)
+
+	// the beacons of the node with its new key verify with the same
+	// distributed key
+	public := NewGrpcClientFromCert(root.opts.certmanager)
+	var resp *drand.PublicRandResponse
+	for i := 0; i < 10; i++ {
+		time.Sleep(period)
+		if resp, err = public.LastPublic(rotated.priv.Public.Address(), oldGroup, true); err == nil && resp.GetRound() > 1 {
+			break
+		}
+	}
+	require.NoError(t, err)
+	require.True(t, resp.GetRound() > 1)
 }
 
 func TestDrandDKGFresh(t *testing.T) {
@@ -217,17 +310,13 @@ func TestDrandDKGFresh(t *testing.T) {
 	period := 1000 * time.Millisecond
 
 	drands, group, dir := BatchNewDrand(n, false,
-		WithCallOption(grpc.FailFast(true)))
+		WithCallOption(grpc.FailFast(true)), WithNodeAuth())
 	defer CloseAllDrands(drands[:n-1])
 	defer os.RemoveAll(dir)
 
 	group.Period = period
 	groupPath := path.Join(dir, "dkggroup.toml")
 	require.NoError(t, key.Save(groupPath, group, false))
-	ids := make([]*key.Identity, n)
-	for i, d := range drands {
-		ids[i] = d.priv.Public
-	}
 
 	var distributedPublic *key.DistPublic
 	var publicSet = make(chan bool)
@@ -268,8 +357,8 @@ func TestDrandDKGFresh(t *testing.T) {
 	setupDrand := func(i int) {
 		//addr := drands[i].priv.Public.Address()
 		myCb := func(b *beacon.Beacon) {
-			msg := beacon.Message(b.PreviousRand, b.Round)
-			err := bls.Verify(key.Pairing, getPublic().Key(), msg, b.Randomness)
+			msg := beacon.Message(group, b.PreviousRand, b.Round)
+			err := group.Scheme.Verify(getPublic().Key(), msg, b.Randomness)
 			if err != nil {
 				fmt.Printf("Beacon error callback: %s\n", b.Randomness)
 			}
@@ -291,6 +380,11 @@ func TestDrandDKGFresh(t *testing.T) {
 		setupDrand(i)
 	}
 
+	require.Equal(t, Fresh, drands[0].State())
+	state, err := StoredState(drands[0].store)
+	require.NoError(t, err)
+	require.Equal(t, Fresh, state)
+
 	var wg sync.WaitGroup
 	wg.Add(n - 1)
 	for _, drand := range drands[1:] {
@@ -323,6 +417,26 @@ func TestDrandDKGFresh(t *testing.T) {
 	close(publicSet)
 	_, err = root.store.LoadShare()
 	require.Nil(t, err)
+	epochs, err := root.store.LoadEpochs()
+	require.NoError(t, err)
+	require.Len(t, epochs, 1)
+	require.True(t, epochs[0].DistPublic().Equal(distributedPublic))
+	require.Equal(t, Serving, root.State())
+	state, err = StoredState(root.store)
+	require.NoError(t, err)
+	require.Equal(t, DKGDone, state)
+
+	// the group is served to clients
+	for _, client := range []*Client{
+		NewGrpcClientFromCert(root.opts.certmanager),
+		NewRESTClientFromCert(root.opts.certmanager),
+	} {
+		served, err := client.Group(root.priv.Public.Addr, root.priv.Public.TLS)
+		require.NoError(t, err)
+		require.Equal(t, n, served.Len())
+		require.Equal(t, period, served.Period)
+		require.True(t, served.PublicKey.Equal(distributedPublic))
+	}
 
 	// make the last node fail
 	// XXX The node still replies to early beacon packet
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/nikkolasg/slog"
+)
+
+// ErrQuorumMismatch is returned when nodes of the group answer with different
+// randomness for the same round.
+var ErrQuorumMismatch = errors.New("drand: nodes disagree on the randomness of the round")
+
+// DefaultRetryBackoff is the time a GroupClient waits before trying again all
+// the nodes of the group, doubled at each retry. It is also the time a failing
+// node is put aside for after its first failure.
+const DefaultRetryBackoff = 1 * time.Second
+
+// maxNodeBackoff is the longest time a failing node is put aside for.
+const maxNodeBackoff = 5 * time.Minute
+
+// GroupClientOption is a function that applies a specific setting to a
+// GroupClient.
+type GroupClientOption func(*GroupClient)
+
+// GroupClient fetches verified public randomness from the nodes of a group. It
+// keeps track of the nodes that fail and tries them last, so requests go to
+// healthy nodes first, and retries all nodes with an exponential backoff when
+// none answers. In quorum mode, each response is confirmed by other nodes of
+// the group before being returned. It works over any transport of Client,
+// gRPC or REST.
+type GroupClient struct {
+	sync.Mutex
+	client  *Client
+	group   *key.Group
+	nodes   []*NodeStatus
+	quorum  int
+	retries int
+	backoff time.Duration
+}
+
+// NodeStatus describes the health of a node as seen by a GroupClient.
+type NodeStatus struct {
+	Identity *key.Identity
+	// Failures is the number of consecutive failed requests to the node.
+	Failures int
+	// LastError is the error of the last failed request, if any.
+	LastError error
+	// Until is the time before which the node is tried only after the
+	// healthy ones.
+	Until time.Time
+}
+
+// NewGroupClient returns a client fetching the randomness of the group through
+// the given client. The group must contain the distributed key.
+func NewGroupClient(c *Client, group *key.Group, opts ...GroupClientOption) (*GroupClient, error) {
+	if group.PublicKey == nil {
+		return nil, errors.New("drand: group has no distributed public key")
+	}
+	g := &GroupClient{
+		client:  c,
+		group:   group,
+		quorum:  1,
+		backoff: DefaultRetryBackoff,
+	}
+	for _, id := range group.Identities() {
+		g.nodes = append(g.nodes, &NodeStatus{Identity: id})
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if len(g.nodes) == 0 {
+		return nil, errors.New("drand: no nodes to contact")
+	}
+	if g.quorum > len(g.nodes) {
+		return nil, fmt.Errorf("drand: quorum of %d with only %d nodes", g.quorum, len(g.nodes))
+	}
+	return g, nil
+}
+
+// WithGroupNodes restricts the nodes contacted to the given ones, tried in
+// this order. They can be reachable at other addresses than the ones of the
+// group.
+func WithGroupNodes(ids ...*key.Identity) GroupClientOption {
+	return func(g *GroupClient) {
+		g.nodes = nil
+		for _, id := range ids {
+			g.nodes = append(g.nodes, &NodeStatus{Identity: id})
+		}
+	}
+}
+
+// WithQuorum requires n nodes to answer with the same randomness for a round
+// before the round is returned.
+func WithQuorum(n int) GroupClientOption {
+	return func(g *GroupClient) {
+		if n > 0 {
+			g.quorum = n
+		}
+	}
+}
+
+// WithRetries sets the number of times all the nodes are tried again when
+// none answers. There is no retry by default.
+func WithRetries(n int) GroupClientOption {
+	return func(g *GroupClient) {
+		g.retries = n
+	}
+}
+
+// WithRetryBackoff sets the time waited before the first retry, doubled at
+// each following one.
+func WithRetryBackoff(d time.Duration) GroupClientOption {
+	return func(g *GroupClient) {
+		g.backoff = d
+	}
+}
+
+// LastPublic returns the latest randomness of the group.
+func (g *GroupClient) LastPublic() (*drand.PublicRandResponse, error) {
+	return g.fetch(func(id *key.Identity) (*drand.PublicRandResponse, error) {
+		return g.client.LastPublic(id.Address(), g.group, id.IsTLS())
+	})
+}
+
+// Public returns the randomness of the group at the given round.
+func (g *GroupClient) Public(round int) (*drand.PublicRandResponse, error) {
+	return g.fetch(func(id *key.Identity) (*drand.PublicRandResponse, error) {
+		return g.client.Public(id.Address(), g.group, id.IsTLS(), round)
+	})
+}
+
+// PublicAt returns the randomness of the first round of the group due at or
+// after the given time.
+func (g *GroupClient) PublicAt(t time.Time) (*drand.PublicRandResponse, error) {
+	return g.fetch(func(id *key.Identity) (*drand.PublicRandResponse, error) {
+		return g.client.PublicAt(id.Address(), g.group, id.IsTLS(), t)
+	})
+}
+
+// Status returns the health of the nodes, in the order they are tried.
+func (g *GroupClient) Status() []NodeStatus {
+	g.Lock()
+	defer g.Unlock()
+	var status []NodeStatus
+	for _, n := range g.order(time.Now()) {
+		status = append(status, *n)
+	}
+	return status
+}
+
+// fetch returns the first valid response to fn, retrying with backoff, and
+// confirms its round with the quorum.
+func (g *GroupClient) fetch(fn func(*key.Identity) (*drand.PublicRandResponse, error)) (*drand.PublicRandResponse, error) {
+	backoff := g.backoff
+	var err error
+	for i := 0; i <= g.retries; i++ {
+		if i > 0 {
+			slog.Debugf("drand: retrying all nodes in %s", backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		var resp *drand.PublicRandResponse
+		var from *NodeStatus
+		resp, from, err = g.first(fn)
+		if err != nil {
+			continue
+		}
+		if g.quorum > 1 {
+			if err = g.confirm(resp, from); err == ErrQuorumMismatch {
+				return nil, err
+			} else if err != nil {
+				continue
+			}
+		}
+		return resp, nil
+	}
+	return nil, err
+}
+
+// first calls fn on each node, healthy ones first, until one succeeds.
+func (g *GroupClient) first(fn func(*key.Identity) (*drand.PublicRandResponse, error)) (*drand.PublicRandResponse, *NodeStatus, error) {
+	var lastErr error
+	for _, n := range g.nodesInOrder() {
+		resp, err := fn(n.Identity)
+		g.report(n, err)
+		if err == nil {
+			return resp, n, nil
+		}
+		slog.Infof("drand: could not get public randomness from %s: %s", n.Identity.Address(), err)
+		lastErr = err
+	}
+	return nil, nil, fmt.Errorf("drand: no node answered, last error: %s", lastErr)
+}
+
+// confirm asks the other nodes for the round of the response until the quorum
+// agrees on it.
+func (g *GroupClient) confirm(resp *drand.PublicRandResponse, from *NodeStatus) error {
+	agree := 1
+	for _, n := range g.nodesInOrder() {
+		if n == from {
+			continue
+		}
+		other, err := g.client.Public(n.Identity.Address(), g.group, n.Identity.IsTLS(), int(resp.GetRound()))
+		g.report(n, err)
+		if err != nil {
+			continue
+		}
+		if !bytes.Equal(other.GetRandomness().GetPoint(), resp.GetRandomness().GetPoint()) {
+			return ErrQuorumMismatch
+		}
+		if agree++; agree >= g.quorum {
+			return nil
+		}
+	}
+	return fmt.Errorf("drand: only %d nodes out of a quorum of %d agree on round %d", agree, g.quorum, resp.GetRound())
+}
+
+func (g *GroupClient) nodesInOrder() []*NodeStatus {
+	g.Lock()
+	defer g.Unlock()
+	return g.order(time.Now())
+}
+
+// order returns the nodes that are not put aside first, in their original
+// order, followed by the others, the ones available soonest first.
+func (g *GroupClient) order(now time.Time) []*NodeStatus {
+	var healthy, failing []*NodeStatus
+	for _, n := range g.nodes {
+		if now.Before(n.Until) {
+			failing = append(failing, n)
+		} else {
+			healthy = append(healthy, n)
+		}
+	}
+	for i := 1; i < len(failing); i++ {
+		for j := i; j > 0 && failing[j].Until.Before(failing[j-1].Until); j-- {
+			failing[j], failing[j-1] = failing[j-1], failing[j]
+		}
+	}
+	return append(healthy, failing...)
+}
+
+// report updates the health of the node after a request. A failing node is
+// put aside for a time doubling at each consecutive failure.
+func (g *GroupClient) report(n *NodeStatus, err error) {
+	g.Lock()
+	defer g.Unlock()
+	if err == nil {
+		n.Failures = 0
+		n.LastError = nil
+		n.Until = time.Time{}
+		return
+	}
+	n.Failures++
+	n.LastError = err
+	backoff := g.backoff
+	for i := 1; i < n.Failures && backoff < maxNodeBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxNodeBackoff {
+		backoff = maxNodeBackoff
+	}
+	n.Until = time.Now().Add(backoff)
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dedis/drand/beacon"
+	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/net"
+	"github.com/dedis/drand/protobuf/crypto"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/dedis/drand/test"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// fakeExternal serves signed beacons for each address, unless the address is
+// down.
+type fakeExternal struct {
+	sync.Mutex
+	down    map[string]bool
+	beacons map[string]*drand.PublicRandResponse
+	calls   map[string]int
+}
+
+func (f *fakeExternal) Public(p net.Peer, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.calls[p.Address()]++
+	if f.down[p.Address()] {
+		return nil, errors.New("node down")
+	}
+	return f.beacons[p.Address()], nil
+}
+
+func (f *fakeExternal) Private(p net.Peer, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeExternal) DistKey(p net.Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeExternal) Group(p net.Peer, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func signedBeacon(t *testing.T, g *key.Group, secret kyber.Scalar, round uint64, prev []byte) *drand.PublicRandResponse {
+	sig, err := g.Scheme.Sign(secret, beacon.Message(g, prev, round))
+	require.NoError(t, err)
+	gid, _ := crypto.GroupToID(g.Scheme.SigGroup)
+	return &drand.PublicRandResponse{
+		Round:      round,
+		Previous:   prev,
+		Randomness: &crypto.Point{Point: sig, Gid: crypto.GroupID(gid)},
+	}
+}
+
+func TestGroupClient(t *testing.T) {
+	n := 3
+	_, group := test.BatchIdentities(n)
+	secret := key.G2.Scalar().Pick(random.New())
+	group.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{key.G2.Point().Mul(secret, nil)}}
+	ids := group.Identities()
+
+	b := signedBeacon(t, group, secret, 10, []byte("previous"))
+	fake := &fakeExternal{
+		down:    map[string]bool{ids[0].Address(): true},
+		beacons: make(map[string]*drand.PublicRandResponse),
+		calls:   make(map[string]int),
+	}
+	for _, id := range ids {
+		fake.beacons[id.Address()] = b
+	}
+	gc, err := NewGroupClient(&Client{client: fake}, group)
+	require.NoError(t, err)
+
+	// the first node fails and is tried last afterwards
+	resp, err := gc.LastPublic()
+	require.NoError(t, err)
+	require.Equal(t, b.GetRound(), resp.GetRound())
+	status := gc.Status()
+	require.Equal(t, ids[0].Address(), status[n-1].Identity.Address())
+	require.Equal(t, 1, status[n-1].Failures)
+	_, err = gc.Public(10)
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.calls[ids[0].Address()])
+	require.Equal(t, 2, fake.calls[ids[1].Address()])
+
+	// invalid beacons are rejected
+	fake.beacons[ids[1].Address()] = &drand.PublicRandResponse{Round: 10, Randomness: b.Randomness}
+	resp, err = gc.LastPublic()
+	require.NoError(t, err)
+	require.Equal(t, b.GetRandomness().GetPoint(), resp.GetRandomness().GetPoint())
+
+	// the quorum confirms the round with the other nodes
+	fake.down[ids[0].Address()] = false
+	fake.beacons[ids[1].Address()] = b
+	gc, err = NewGroupClient(&Client{client: fake}, group, WithQuorum(3))
+	require.NoError(t, err)
+	_, err = gc.LastPublic()
+	require.NoError(t, err)
+
+	fake.beacons[ids[2].Address()] = signedBeacon(t, group, secret, 10, []byte("fork"))
+	_, err = gc.LastPublic()
+	require.Equal(t, ErrQuorumMismatch, err)
+
+	fake.beacons[ids[2].Address()] = b
+	fake.down[ids[2].Address()] = true
+	_, err = gc.LastPublic()
+	require.Error(t, err)
+
+	_, err = NewGroupClient(&Client{client: fake}, group, WithQuorum(n+1))
+	require.Error(t, err)
+
+	// no node answers, even after retrying
+	for _, id := range ids {
+		fake.down[id.Address()] = true
+	}
+	gc, err = NewGroupClient(&Client{client: fake}, group, WithRetries(1), WithRetryBackoff(0))
+	require.NoError(t, err)
+	_, err = gc.LastPublic()
+	require.Error(t, err)
+}
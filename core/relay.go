@@ -0,0 +1,264 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dedis/drand/beacon"
+	"github.com/dedis/drand/fs"
+	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/net"
+	"github.com/dedis/drand/protobuf/crypto"
+	dkg_proto "github.com/dedis/drand/protobuf/dkg"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/nikkolasg/slog"
+)
+
+// Relay is a read-only mirror of the public randomness of a drand network. It
+// holds no share: it follows the nodes of the group, verifies each beacon
+// against the distributed key of the group, stores it and serves the public
+// API, over gRPC and REST, like a drand node does. Relays let clients fetch
+// randomness from scalable endpoints while the nodes stay behind a firewall.
+type Relay struct {
+	opts    *Config
+	group   *key.Group
+	nodes   []*key.Identity
+	client  *Client
+	store   beacon.Store
+	l       net.Listener
+	addr    string
+	current int
+
+	sync.Mutex
+	done chan bool
+	// running is done once Run returned
+	running sync.WaitGroup
+}
+
+// NewRelay returns a relay following the given nodes. Their addresses must
+// serve the public API: the addresses of the group only do when the nodes do
+// not use a separate public address, see WithPublicListenAddress. The group
+// must contain the distributed key. The relay serves the public API on the
+// listen address of the config, set with WithListenAddress, and stores the
+// beacons in its db folder.
+func NewRelay(group *key.Group, c *Config, nodes ...*key.Identity) (*Relay, error) {
+	if group.PublicKey == nil {
+		return nil, errors.New("relay: group has no distributed public key")
+	}
+	if c.listenAddr == "" {
+		return nil, errors.New("relay: no listen address given")
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("relay: no nodes to follow given")
+	}
+	fs.CreateSecureFolder(c.DBFolder())
+	store, err := beacon.NewBoltStore(c.DBFolder(), c.boltOpts)
+	if err != nil {
+		return nil, err
+	}
+	r := &Relay{
+		opts:   c,
+		group:  group,
+		nodes:  nodes,
+		client: NewGrpcClientFromCert(c.certmanager, c.dialOptions()...),
+		store:  beacon.NewCallbackStore(store, c.callbacks),
+		addr:   c.listenAddr,
+		done:   make(chan bool),
+	}
+	r.l, err = c.newPublicListener(c.listenAddr, r, net.WithLatestMaxAge(r.latestMaxAge))
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	go r.l.Start()
+	return r, nil
+}
+
+// Run follows the nodes until Stop is called: at each period of the group, it
+// fetches the latest beacon and the ones missed since the last beacon stored.
+// It returns when the relay is stopped.
+func (r *Relay) Run() {
+	r.Lock()
+	select {
+	case <-r.done:
+		r.Unlock()
+		return
+	default:
+	}
+	r.running.Add(1)
+	r.Unlock()
+	defer r.running.Done()
+
+	period := getPeriod(r.group)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	slog.Infof("relay: following %d nodes every %s", len(r.nodes), period)
+	for {
+		if err := r.Sync(); err != nil {
+			slog.Infof("relay: %s", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// Sync fetches the latest beacon from the nodes, trying each of them in turn
+// until one answers with a valid beacon, and stores it alongside the beacons
+// of the rounds missed since the last one stored. At most MaxRelayCatchUp
+// missed rounds are fetched at each call: the latest beacon is only stored once
+// the relay caught up, the following calls fetching the next missed rounds.
+// When the store is empty, only the latest beacon is stored.
+func (r *Relay) Sync() error {
+	latest, err := r.fetch(func(id *key.Identity) (*drand.PublicRandResponse, error) {
+		return r.client.LastPublic(id.Address(), r.group, id.IsTLS())
+	})
+	if err != nil {
+		return fmt.Errorf("can't fetch latest beacon: %s", err)
+	}
+	if last, err := r.store.Last(); err == nil {
+		if last.Round >= latest.GetRound() {
+			return nil
+		}
+		end := latest.GetRound()
+		if end-last.Round-1 > MaxRelayCatchUp {
+			end = last.Round + 1 + MaxRelayCatchUp
+		}
+		for round := last.Round + 1; round < end; round++ {
+			select {
+			case <-r.done:
+				return errors.New("relay stopped")
+			default:
+			}
+			resp, err := r.fetch(func(id *key.Identity) (*drand.PublicRandResponse, error) {
+				return r.client.Public(id.Address(), r.group, id.IsTLS(), int(round))
+			})
+			if err != nil {
+				// the nodes may have skipped that round
+				slog.Debugf("relay: can't fetch round %d: %s", round, err)
+				continue
+			}
+			if err := r.put(resp); err != nil {
+				return err
+			}
+		}
+		if end < latest.GetRound() {
+			slog.Infof("relay: caught up to round %d of %d", end-1, latest.GetRound())
+			return nil
+		}
+	}
+	return r.put(latest)
+}
+
+// fetch calls fn on the node that answered last, then on the following nodes
+// of the list until one succeeds.
+func (r *Relay) fetch(fn func(*key.Identity) (*drand.PublicRandResponse, error)) (*drand.PublicRandResponse, error) {
+	r.Lock()
+	start := r.current
+	r.Unlock()
+	var err error
+	for i := 0; i < len(r.nodes); i++ {
+		idx := (start + i) % len(r.nodes)
+		id := r.nodes[idx]
+		var resp *drand.PublicRandResponse
+		resp, err = fn(id)
+		if err != nil {
+			slog.Debugf("relay: node %s failed: %s", id.Address(), err)
+			continue
+		}
+		if idx != start {
+			slog.Infof("relay: now following %s", id.Address())
+		}
+		r.Lock()
+		r.current = idx
+		r.Unlock()
+		return resp, nil
+	}
+	return nil, err
+}
+
+// put stores a verified beacon. Its time is not stored, it is given by the
+// group.
+func (r *Relay) put(resp *drand.PublicRandResponse) error {
+	return r.store.Put(&beacon.Beacon{
+		PreviousRand: resp.GetPrevious(),
+		Round:        resp.GetRound(),
+		Randomness:   resp.GetRandomness().GetPoint(),
+		Gid:          int32(resp.GetRandomness().GetGid()),
+	})
+}
+
+// latestMaxAge returns how long the latest beacon can be cached by clients:
+// until the next round is due.
+func (r *Relay) latestMaxAge() time.Duration {
+	return untilNextRound(r.group, r.store)
+}
+
+// Stop stops following the nodes and serving the public API. It waits for Run
+// to return before closing the store.
+func (r *Relay) Stop() {
+	r.Lock()
+	select {
+	case <-r.done:
+		r.Unlock()
+		return
+	default:
+		close(r.done)
+	}
+	r.Unlock()
+	r.running.Wait()
+	r.l.Stop()
+	r.store.Close()
+}
+
+// Public returns a stored beacon according to the request, as drand nodes do.
+func (r *Relay) Public(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	return publicFromStore(r.group, r.store, in)
+}
+
+// Private is not served by relays since they hold no long-term key.
+func (r *Relay) Private(c context.Context, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	return nil, errors.New("relay: private randomness is only served by drand nodes")
+}
+
+// DistKey returns the distributed key of the group the relay follows. Relays
+// keep no history, so it is returned for any round.
+func (r *Relay) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	pt, err := crypto.KyberToProtoPoint(r.group.PublicKey.Key())
+	if err != nil {
+		return nil, err
+	}
+	return &drand.DistKeyResponse{Key: pt}, nil
+}
+
+// Group returns the chain hash of the group the relay follows.
+func (r *Relay) Group(c context.Context, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	return groupResponse(r.group)
+}
+
+// Home ...
+func (r *Relay) Home(c context.Context, in *drand.HomeRequest) (*drand.HomeResponse, error) {
+	return &drand.HomeResponse{
+		Status: fmt.Sprintf("drand relay up and running on %s", r.addr),
+	}, nil
+}
+
+// NewBeacon is not served by relays.
+func (r *Relay) NewBeacon(c context.Context, in *drand.BeaconRequest) (*drand.BeaconResponse, error) {
+	return nil, errors.New("relay: not a drand node")
+}
+
+// Setup is not served by relays.
+func (r *Relay) Setup(c context.Context, in *dkg_proto.DKGPacket) (*dkg_proto.DKGResponse, error) {
+	return nil, errors.New("relay: not a drand node")
+}
+
+// Reshare is not served by relays.
+func (r *Relay) Reshare(c context.Context, in *dkg_proto.ResharePacket) (*dkg_proto.ReshareResponse, error) {
+	return nil, errors.New("relay: not a drand node")
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/net"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/dedis/drand/test"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// fakeChain serves the beacons of a chain up to its latest round, unless the
+// address is down.
+type fakeChain struct {
+	sync.Mutex
+	down    map[string]bool
+	beacons []*drand.PublicRandResponse
+	// delay is the time taken by each request
+	delay    time.Duration
+	inFlight int32
+}
+
+func (f *fakeChain) Public(p net.Peer, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	time.Sleep(f.delay)
+	f.Lock()
+	defer f.Unlock()
+	if f.down[p.Address()] {
+		return nil, errors.New("node down")
+	}
+	if in.GetRound() == 0 {
+		return f.beacons[len(f.beacons)-1], nil
+	}
+	if in.GetRound() > uint64(len(f.beacons)) {
+		return nil, errors.New("round not produced yet")
+	}
+	return f.beacons[in.GetRound()-1], nil
+}
+
+func (f *fakeChain) Private(p net.Peer, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeChain) DistKey(p net.Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeChain) Group(p net.Peer, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+// extend signs the beacons of the chain up to the given round.
+func (f *fakeChain) extend(t *testing.T, g *key.Group, secret kyber.Scalar, round uint64) {
+	f.Lock()
+	defer f.Unlock()
+	for r := uint64(len(f.beacons)) + 1; r <= round; r++ {
+		prev := []byte("genesis")
+		if r > 1 {
+			prev = f.beacons[r-2].GetRandomness().GetPoint()
+		}
+		f.beacons = append(f.beacons, signedBeacon(t, g, secret, r, prev))
+	}
+}
+
+func TestRelay(t *testing.T) {
+	n := 3
+	_, group := test.BatchIdentities(n)
+	secret := key.G2.Scalar().Pick(random.New())
+	group.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{key.G2.Point().Mul(secret, nil)}}
+	ids := group.Identities()
+	dir, err := ioutil.TempDir("", "relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	relayAddr := test.Addresses(1)[0]
+	conf := NewConfig(WithInsecure(), WithListenAddress(relayAddr), WithDbFolder(dir))
+	// the public endpoints of the nodes must be given
+	_, err = NewRelay(group, conf)
+	require.Error(t, err)
+
+	relay, err := NewRelay(group, conf, ids...)
+	require.NoError(t, err)
+	defer relay.Stop()
+	chain := &fakeChain{down: map[string]bool{ids[0].Address(): true}}
+	relay.client = &Client{client: chain}
+
+	// an empty relay only stores the latest beacon, from the first node up
+	chain.extend(t, group, secret, 5)
+	require.NoError(t, relay.Sync())
+	last, err := relay.store.Last()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), last.Round)
+	_, err = relay.store.Get(4)
+	require.Error(t, err)
+
+	// the missed rounds are fetched at most MaxRelayCatchUp at a time
+	latest := uint64(5 + MaxRelayCatchUp + 10)
+	chain.extend(t, group, secret, latest)
+	require.NoError(t, relay.Sync())
+	last, err = relay.store.Last()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5+MaxRelayCatchUp), last.Round)
+	require.NoError(t, relay.Sync())
+	last, err = relay.store.Last()
+	require.NoError(t, err)
+	require.Equal(t, latest, last.Round)
+	for r := uint64(5); r <= latest; r++ {
+		_, err := relay.store.Get(r)
+		require.NoError(t, err)
+	}
+
+	// invalid beacons are not stored
+	chain.Lock()
+	chain.beacons = append(chain.beacons, &drand.PublicRandResponse{Round: latest + 1, Randomness: chain.beacons[0].Randomness})
+	chain.Unlock()
+	require.Error(t, relay.Sync())
+
+	// the relay serves the beacons it stored
+	resp, err := NewGrpcClient().LastPublic(relayAddr, group, false)
+	require.NoError(t, err)
+	require.Equal(t, latest, resp.GetRound())
+	resp, err = NewRESTClient().Public(relayAddr, group, false, 10)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), resp.GetRound())
+}
+
+func TestRelayStop(t *testing.T) {
+	_, group := test.BatchIdentities(3)
+	secret := key.G2.Scalar().Pick(random.New())
+	group.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{key.G2.Point().Mul(secret, nil)}}
+	dir, err := ioutil.TempDir("", "relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	conf := NewConfig(WithInsecure(), WithListenAddress(test.Addresses(1)[0]), WithDbFolder(dir))
+	relay, err := NewRelay(group, conf, group.Identities()...)
+	require.NoError(t, err)
+	chain := &fakeChain{delay: 200 * time.Millisecond}
+	chain.extend(t, group, secret, 5)
+	relay.client = &Client{client: chain}
+
+	// the relay is stopped while fetching a beacon: Stop waits for Run to
+	// return before closing the store
+	go relay.Run()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&chain.inFlight))
+	relay.Stop()
+	require.Equal(t, int32(0), atomic.LoadInt32(&chain.inFlight))
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dedis/drand/key"
+	"github.com/nikkolasg/slog"
+)
+
+// State is the state of a drand node.
+type State int
+
+const (
+	// Fresh nodes have a long-term key but no share of a distributed key:
+	// they wait for a DKG.
+	Fresh State = iota
+	// DKGDone nodes hold a share of the distributed key of their group and
+	// run the randomness beacon once started.
+	DKGDone
+	// Serving nodes are running the randomness beacon.
+	Serving
+)
+
+func (s State) String() string {
+	switch s {
+	case Fresh:
+		return "fresh"
+	case DKGDone:
+		return "dkg-done"
+	case Serving:
+		return "serving"
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
+// ErrBeaconDBExists is returned when starting a fresh node over an existing
+// beacon database. The database belongs to a previous distributed key, and
+// drand only supports one at a time.
+var ErrBeaconDBExists = errors.New("drand: a beacon database exists already for a fresh node")
+
+// StoredState returns the state of the node whose keys are in the store,
+// Fresh or DKGDone. Only missing files make a node fresh: it returns an error
+// if the group, the share or the distributed key can not be read, for example
+// when the share can not be decrypted.
+func StoredState(s key.Store) (State, error) {
+	group, errG := s.LoadGroup()
+	share, errS := s.LoadShare()
+	dist, errD := s.LoadDistPublic()
+	for _, err := range []error{errG, errS, errD} {
+		if err != nil && !os.IsNotExist(err) {
+			return Fresh, err
+		}
+	}
+	if errG != nil || errS != nil || errD != nil || group == nil || share == nil || dist == nil {
+		return Fresh, nil
+	}
+	return DKGDone, nil
+}
+
+// State returns the current state of the node.
+func (d *Drand) State() State {
+	d.state.Lock()
+	defer d.state.Unlock()
+	switch {
+	case d.beacon != nil:
+		return Serving
+	case d.share != nil:
+		return DKGDone
+	}
+	return Fresh
+}
+
+// StartDrand starts the node of the store according to its stored state: a
+// fresh node waits for a DKG, and a node which ran one starts the beacon,
+// catching up with the other nodes. A fresh node refuses to start over an
+// existing beacon database, unless it is configured to remove it with
+// WithResetDB.
+func StartDrand(s key.Store, c *Config) (*Drand, error) {
+	state, err := StoredState(s)
+	if err != nil {
+		return nil, err
+	}
+	if state == DKGDone {
+		slog.Infof("drand: will already start running randomness beacon")
+		d, err := LoadDrand(s, c)
+		if err != nil {
+			return nil, err
+		}
+		// XXX make it configurable so that new share holder can still start if
+		// nobody started.
+		if err := d.StartBeacon(true); err != nil {
+			d.Stop()
+			return nil, err
+		}
+		return d, nil
+	}
+	if _, err := os.Stat(c.DBFolder()); err == nil {
+		if !c.resetDB {
+			return nil, ErrBeaconDBExists
+		}
+		if err := ResetBeaconDB(c); err != nil {
+			return nil, err
+		}
+		slog.Infof("drand: removed existing beacon database %s", c.DBFolder())
+	}
+	slog.Infof("drand: will run as fresh install -> expect to run DKG.")
+	return NewDrand(s, c)
+}
+
+// ResetBeaconDB removes the beacon database of the node. The node must not be
+// running.
+func ResetBeaconDB(c *Config) error {
+	return os.RemoveAll(c.DBFolder())
+}
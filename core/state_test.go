@@ -0,0 +1,29 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/dedis/drand/key"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// missing files make a fresh node
+	store := key.NewFileStore(dir)
+	state, err := StoredState(store)
+	require.NoError(t, err)
+	require.Equal(t, Fresh, state)
+
+	// other errors are returned
+	groupPath := path.Join(dir, key.GroupFolderName, "drand_group.toml")
+	require.NoError(t, ioutil.WriteFile(groupPath, []byte("not a group"), 0600))
+	_, err = StoredState(store)
+	require.Error(t, err)
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"os"
-	"runtime"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/dedis/drand/core"
 	"github.com/dedis/drand/key"
@@ -10,47 +13,110 @@ import (
 	"github.com/urfave/cli"
 )
 
+// stopDaemon polls the daemon every stopWaitPeriod, at most stopWaitAttempts
+// times, until it has stopped. This leaves time for the listeners to drain.
+const (
+	stopWaitPeriod   = 200 * time.Millisecond
+	stopWaitAttempts = 50
+)
+
 func startCmd(c *cli.Context) error {
 	conf := contextToConfig(c)
-	fs := key.NewFileStore(conf.ConfigFolder())
-	var drand *core.Drand
+	fs := keyStore(c, conf.ConfigFolder())
+	drand, err := core.StartDrand(fs, conf)
+	checkUnlocked(err)
+	if err == core.ErrBeaconDBExists {
+		slog.Fatalf("%s: %s, remove it with --reset-db or `drand db reset`", err, conf.DBFolder())
+	} else if err != nil {
+		slog.Fatalf("drand: can't start drand instance: %s", err)
+	}
+	slog.Infof("drand: started, node is %s", drand.State())
+	// run until stopped by `drand stop` or a signal, which take the same
+	// graceful path
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case sig := <-signals:
+		slog.Infof("drand: received %s, shutting down", sig)
+		drand.Stop()
+	case <-drand.WaitExit():
+	}
+	slog.Infof("drand: stopped")
+	return nil
+}
 
-	// determine if we already ran a DKG or not
-	_, errG := fs.LoadGroup()
-	_, errS := fs.LoadShare()
-	_, errD := fs.LoadDistPublic()
-	// XXX place that logic inside core/ directly with only one method
-	freshRun := errG != nil || errS != nil || errD != nil
-	var err error
-	if freshRun {
-		if exit := resetBeaconDB(conf); exit {
-			os.Exit(0)
-		}
-		slog.Infof("drand: will run as fresh install -> expect to run DKG.")
-		drand, err = core.NewDrand(fs, conf)
-		if err != nil {
-			slog.Fatalf("drand: can't instantiate drand instance %s", err)
-		}
-	} else {
-		slog.Infof("drand: will already start running randomness beacon")
-		drand, err = core.LoadDrand(fs, conf)
-		if err != nil {
-			slog.Fatalf("drand: can't load drand instance %s", err)
-		}
-		// XXX make it configurable so that new share holder can still start if
-		// nobody started.
-		if err := drand.StartBeacon(true); err != nil {
-			slog.Fatalf("drand: starting beacon failed: %s", err)
+// relayCmd runs a relay following the nodes of the group given as argument and
+// serving their public randomness.
+func relayCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatalUsage("relay expects a group argument")
+	}
+	if !c.IsSet(listenFlag.Name) {
+		fatalUsage("relay requires a listening address with --listen")
+	}
+	conf := contextToConfig(c)
+	group := getGroup(c)
+	// the addresses of the group only serve the public API when the nodes
+	// have no separate public address, so the operator lists the endpoints
+	var nodes []*key.Identity
+	switch {
+	case c.IsSet(connectFlag.Name) && c.IsSet(nodeFlag.Name):
+		fatalUsage("relay expects either --connect or --nodes")
+	case c.IsSet(connectFlag.Name):
+		for _, addr := range strings.Split(c.String(connectFlag.Name), ",") {
+			nodes = append(nodes, &key.Identity{Addr: strings.TrimSpace(addr), TLS: !c.Bool(insecureFlag.Name)})
 		}
+	case c.IsSet(nodeFlag.Name):
+		nodes = getNodes(c)
+	default:
+		fatalUsage("relay requires the public addresses of the nodes to follow with --connect, " +
+			"or their addresses in the group with --nodes if they serve the public API there")
+	}
+	relay, err := core.NewRelay(group, conf, nodes...)
+	if err != nil {
+		slog.Fatalf("drand: can't start relay: %s", err)
 	}
-	// wait indefinitely  - XXX analyzes goroutine graphs to see if it actually
-	// makes sense
-	runtime.Goexit()
+	slog.Infof("drand: relay serving public randomness on %s", c.String(listenFlag.Name))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Infof("drand: received %s, shutting down relay", sig)
+		relay.Stop()
+	}()
+	relay.Run()
+	return nil
+}
 
+// resetDBCmd removes the beacon database of the node. The daemon must be
+// stopped.
+func resetDBCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	client := controlClient(c)
+	if err := client.Ping(); err == nil {
+		slog.Fatalf("drand: the daemon is running on port %s, stop it first", controlPort(c))
+	}
+	if err := core.ResetBeaconDB(conf); err != nil {
+		slog.Fatalf("drand: can't remove the beacon database: %s", err)
+	}
+	printInfo("drand: removed the beacon database %s", conf.DBFolder())
 	return nil
 }
 
+// stopDaemon asks the daemon to shut down gracefully and waits until it no
+// longer answers on its control port.
 func stopDaemon(c *cli.Context) error {
-	// TODO
-	panic("not implemented yet")
+	client := controlClient(c)
+	if _, err := client.Shutdown(); err != nil {
+		slog.Fatalf("drand: can't stop the daemon: %s", err)
+	}
+	for i := 0; i < stopWaitAttempts; i++ {
+		if err := client.Ping(); err != nil {
+			printInfo("drand daemon stopped")
+			return nil
+		}
+		time.Sleep(stopWaitPeriod)
+	}
+	slog.Fatalf("drand: daemon still running after %s", stopWaitAttempts*stopWaitPeriod)
+	return nil
 }
@@ -0,0 +1,167 @@
+// Package derive turns a verified drand beacon into usable randomness. All
+// functions are deterministic: anyone holding the same beacon obtains the same
+// outputs, which makes them suitable for publicly verifiable lotteries and
+// committee selections.
+//
+// The derivation works as follows:
+//   - the randomness of a beacon is SHA-256(signature), 32 bytes long.
+//   - a Source expands this randomness into a stream of bytes for a given
+//     context. The i-th block of 32 bytes of the stream is
+//     HMAC-SHA256(randomness, context || uint64(i)) with i in big endian,
+//     starting at zero.
+//   - integers are read from the stream as big endian uint64. Integers in a
+//     range are obtained by rejection sampling, so there is no modulo bias.
+//
+// Different applications using the same beacon should use different contexts
+// so their outputs are independent.
+package derive
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Size is the length in bytes of the randomness derived from a beacon.
+const Size = sha256.Size
+
+// Randomness returns the 32-byte randomness derived from the given beacon
+// signature. The signature must have been verified beforehand.
+func Randomness(signature []byte) []byte {
+	h := sha256.Sum256(signature)
+	return h[:]
+}
+
+// Source is a deterministic stream of random values expanded from the
+// randomness of a beacon. It implements io.Reader. A Source is not safe for
+// concurrent use.
+type Source struct {
+	randomness []byte
+	context    []byte
+	counter    uint64
+	buff       []byte
+}
+
+// NewSource returns a Source expanding the given randomness, as returned by
+// Randomness, for the given context.
+func NewSource(randomness []byte, context string) *Source {
+	return &Source{
+		randomness: randomness,
+		context:    []byte(context),
+	}
+}
+
+// Read fills p with the next bytes of the stream. It never returns an error.
+func (s *Source) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.buff) == 0 {
+			s.next()
+		}
+		c := copy(p[n:], s.buff)
+		s.buff = s.buff[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (s *Source) next() {
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], s.counter)
+	s.counter++
+	h := hmac.New(sha256.New, s.randomness)
+	h.Write(s.context)
+	h.Write(ctr[:])
+	s.buff = h.Sum(nil)
+}
+
+// Uint64 returns the next 8 bytes of the stream as a big endian uint64.
+func (s *Source) Uint64() uint64 {
+	var b [8]byte
+	s.Read(b[:])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Uint64n returns a uniform integer in [0,n). It panics if n is zero.
+func (s *Source) Uint64n(n uint64) uint64 {
+	if n == 0 {
+		panic("derive: invalid argument to Uint64n")
+	}
+	// values above max would give more weight to the smallest results
+	max := math.MaxUint64 - (math.MaxUint64%n+1)%n
+	for {
+		v := s.Uint64()
+		if v <= max {
+			return v % n
+		}
+	}
+}
+
+// Range returns a uniform integer in [min,max).
+func (s *Source) Range(min, max uint64) (uint64, error) {
+	if max <= min {
+		return 0, errors.New("derive: empty range")
+	}
+	return min + s.Uint64n(max-min), nil
+}
+
+// Shuffle pseudo-randomizes the order of n elements using the Fisher-Yates
+// algorithm. swap swaps the elements with indexes i and j.
+func (s *Source) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := int(s.Uint64n(uint64(i) + 1))
+		swap(i, j)
+	}
+}
+
+// Permutation returns a uniform permutation of the integers [0,n).
+func (s *Source) Permutation(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	s.Shuffle(n, func(i, j int) { p[i], p[j] = p[j], p[i] })
+	return p
+}
+
+// WeightedSample selects k distinct indexes of weights without replacement.
+// At each step, an index not yet selected is chosen with a probability
+// proportional to its weight. Indexes with a zero weight are never selected.
+// The indexes are returned in the order they were selected.
+func (s *Source) WeightedSample(weights []uint64, k int) ([]int, error) {
+	var total uint64
+	var candidates int
+	for _, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if total+w < total {
+			return nil, errors.New("derive: total weight overflows")
+		}
+		total += w
+		candidates++
+	}
+	if k < 0 || k > candidates {
+		return nil, errors.New("derive: not enough candidates with positive weight")
+	}
+	selected := make([]bool, len(weights))
+	res := make([]int, 0, k)
+	for len(res) < k {
+		r := s.Uint64n(total)
+		for i, w := range weights {
+			if selected[i] || w == 0 {
+				continue
+			}
+			if r < w {
+				selected[i] = true
+				total -= w
+				res = append(res, i)
+				break
+			}
+			r -= w
+		}
+	}
+	return res, nil
+}
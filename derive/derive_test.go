@@ -0,0 +1,81 @@
+package derive
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors computed from a fake signature made of the bytes 0 to 63
+func vectorRandomness(t *testing.T) []byte {
+	sig := make([]byte, 64)
+	for i := range sig {
+		sig[i] = byte(i)
+	}
+	r := Randomness(sig)
+	require.Len(t, r, Size)
+	require.Equal(t, "fdeab9acf3710362bd2658cdc9a29e8f9c757fcf9811603a8c447cd1d9151108", hex.EncodeToString(r))
+	return r
+}
+
+func TestSourceStream(t *testing.T) {
+	r := vectorRandomness(t)
+
+	s := NewSource(r, "")
+	require.Equal(t, uint64(10991448081745883821), s.Uint64())
+	require.Equal(t, uint64(9144949791621571089), s.Uint64())
+
+	// reading across block boundaries
+	s = NewSource(r, "test")
+	buff := make([]byte, 40)
+	n, err := s.Read(buff[:20])
+	require.NoError(t, err)
+	require.Equal(t, 20, n)
+	_, err = s.Read(buff[20:])
+	require.NoError(t, err)
+	exp := "532e4a81448f30aa26e099c7416e5f0ade0b4fff93276781f68a5cd1630a8757cb68d7d56ddb7095"
+	require.Equal(t, exp, hex.EncodeToString(buff))
+}
+
+func TestSourceRange(t *testing.T) {
+	r := vectorRandomness(t)
+	s := NewSource(r, "test")
+	var values []uint64
+	for i := 0; i < 5; i++ {
+		v, err := s.Range(10, 110)
+		require.NoError(t, err)
+		values = append(values, v)
+	}
+	require.Equal(t, []uint64{76, 84, 55, 41, 79}, values)
+
+	_, err := s.Range(10, 10)
+	require.Error(t, err)
+	require.Panics(t, func() { s.Uint64n(0) })
+}
+
+func TestSourcePermutation(t *testing.T) {
+	r := vectorRandomness(t)
+	p := NewSource(r, "test").Permutation(10)
+	require.Equal(t, []int{5, 0, 9, 8, 2, 7, 4, 1, 3, 6}, p)
+	require.Equal(t, p, NewSource(r, "test").Permutation(10))
+	require.NotEqual(t, p, NewSource(r, "other").Permutation(10))
+}
+
+func TestSourceWeightedSample(t *testing.T) {
+	r := vectorRandomness(t)
+	weights := []uint64{1, 0, 5, 10, 3, 1}
+	sample, err := NewSource(r, "test").WeightedSample(weights, 3)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 2, 0}, sample)
+
+	all, err := NewSource(r, "test").WeightedSample(weights, 5)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	require.NotContains(t, all, 1)
+
+	_, err = NewSource(r, "test").WeightedSample(weights, 6)
+	require.Error(t, err)
+	_, err = NewSource(r, "test").WeightedSample([]uint64{1 << 63, 1 << 63}, 1)
+	require.Error(t, err)
+}
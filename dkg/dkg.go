@@ -2,6 +2,7 @@ package dkg
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -82,6 +83,11 @@ func NewHandler(n Network, c *Config) (*Handler, error) {
 	if c.Timeout == time.Duration(0) {
 		c.Timeout = DefaultTimeout
 	}
+	if c.Key.Key == nil {
+		// the kyber DKG encrypts and signs the deals with the private scalar
+		// itself, so it can not delegate to an external signer
+		return nil, errors.New("dkg: the long-term private key must be loaded to run a DKG, it can't be held by an external signer")
+	}
 	cdkg := &dkg.Config{
 		Suite:        c.Suite.(dkg.Suite),
 		Longterm:     c.Key.Key,
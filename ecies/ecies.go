@@ -79,11 +79,23 @@ func Encrypt(g kyber.Group, fn func() hash.Hash, public kyber.Point, msg []byte)
 // and the derivation of the symmetric key. It finally tries to decrypt the
 // ciphertext and returns the plaintext if successful, an error otherwise.
 func Decrypt(g kyber.Group, fn func() hash.Hash, priv kyber.Scalar, o *drand.ECIESObject) ([]byte, error) {
+	return DecryptWith(g, fn, func(eph kyber.Point) (kyber.Point, error) {
+		return g.Point().Mul(priv, eph), nil
+	}, o)
+}
+
+// DecryptWith decrypts like Decrypt, the DH exchange with the ephemeral point
+// being performed by the given function. It allows to decrypt with a private
+// key held outside of the process.
+func DecryptWith(g kyber.Group, fn func() hash.Hash, dhFn func(kyber.Point) (kyber.Point, error), o *drand.ECIESObject) ([]byte, error) {
 	eph, err := crypto.ProtoToKyberPoint(o.GetEphemeral())
 	if err != nil {
 		return nil, err
 	}
-	dh := g.Point().Mul(priv, eph)
+	dh, err := dhFn(eph)
+	if err != nil {
+		return nil, err
+	}
 	dhBuff, err := dh.MarshalBinary()
 	if err != nil {
 		return nil, err
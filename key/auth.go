@@ -0,0 +1,54 @@
+package key
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// authSuite gives a source of randomness to the group of a long-term key, as
+// needed by the schnorr package.
+type authSuite struct {
+	kyber.Group
+}
+
+func (a *authSuite) RandomStream() cipher.Stream {
+	return random.New()
+}
+
+// keyGroup returns the group of the given long-term key.
+func keyGroup(p kyber.Point) (kyber.Group, error) {
+	for _, g := range []kyber.Group{G2, G1} {
+		if InGroup(p, g) {
+			return g, nil
+		}
+	}
+	return nil, errors.New("key: long-term key on an unknown group")
+}
+
+// AuthSign returns a Schnorr signature of the message under the long-term key
+// of the pair. Nodes use it to authenticate the requests they send to each
+// other, it is not related to the randomness.
+func (p *Pair) AuthSign(msg []byte) ([]byte, error) {
+	if p.signer != nil {
+		return p.signer.AuthSign(msg)
+	}
+	g, err := keyGroup(p.Public.Key)
+	if err != nil {
+		return nil, err
+	}
+	return schnorr.Sign(&authSuite{g}, p.Key, msg)
+}
+
+// AuthVerify checks a signature produced by AuthSign with the private key of
+// this identity.
+func (i *Identity) AuthVerify(msg, sig []byte) error {
+	g, err := keyGroup(i.Key)
+	if err != nil {
+		return err
+	}
+	return schnorr.Verify(g, i.Key, msg, sig)
+}
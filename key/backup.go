@@ -0,0 +1,450 @@
+package key
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// BackupVersion is the version of the backup archive format written by
+// Backup.Write.
+const BackupVersion = 1
+
+// names of the entries of a backup archive, mirroring the layout of the
+// config folder
+const (
+	backupManifest = "manifest.toml"
+	// name authenticated with the content of the archive
+	backupName = "drand-backup"
+)
+
+var (
+	backupPrivateKey = path.Join(KeyFolderName, keyFileName+privateExtension)
+	backupPublicKey  = path.Join(KeyFolderName, keyFileName+publicExtension)
+	backupGroup      = path.Join(GroupFolderName, groupFileName)
+	backupShare      = path.Join(GroupFolderName, shareFileName)
+	backupDistKey    = path.Join(GroupFolderName, distKeyFileName)
+	backupDB         = path.Join("db", "drand.db")
+)
+
+// Backup holds the material of a node saved in a backup archive.
+type Backup struct {
+	// Pair is the long-term key pair. Its private key is nil when it is held
+	// by an external signer, and only the public identity is saved.
+	Pair *Pair
+	// Share, DistPublic and Group are nil before the first DKG.
+	Share      *Share
+	DistPublic *DistPublic
+	Group      *Group
+	// Epochs is the history of the node, with the shares of the epochs the
+	// store kept.
+	Epochs []*Epoch
+	// DB is the content of the beacon database, nil if it is not backed up.
+	DB []byte
+}
+
+// BackupManifest lists the files of a backup archive with their SHA-256
+// hashes, checked when the archive is read.
+type BackupManifest struct {
+	Version int
+	Created string
+	Address string
+	Files   []*BackupFile
+}
+
+// BackupFile describes a file of a backup archive.
+type BackupFile struct {
+	Name   string
+	Size   int
+	SHA256 string
+}
+
+// Hash returns the integrity hash of the archive, the SHA-256 hash of the
+// names and hashes of all its files. It identifies the content of a backup
+// independently of its encryption.
+func (m *BackupManifest) Hash() string {
+	h := sha256.New()
+	for _, f := range m.Files {
+		h.Write([]byte(f.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(f.SHA256))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewBackup returns a backup of the material in the store. The key pair must
+// be present; the share, the distributed key, the group and the history of
+// epochs are saved if the store has them. The beacon database is left to the caller.
+func NewBackup(s Store) (*Backup, error) {
+	b := new(Backup)
+	var err error
+	if b.Pair, err = s.LoadKeyPair(); err != nil {
+		return nil, err
+	}
+	if b.Share, err = s.LoadShare(); os.IsNotExist(err) {
+		b.Share = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if b.DistPublic, err = s.LoadDistPublic(); os.IsNotExist(err) {
+		b.DistPublic = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if b.Group, err = s.LoadGroup(); os.IsNotExist(err) {
+		b.Group = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if b.Epochs, err = s.LoadEpochs(); err != nil {
+		return nil, err
+	}
+	for _, e := range b.Epochs {
+		if e.Share, err = s.LoadEpochShare(e.Number); os.IsNotExist(err) {
+			e.Share = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// backupEpoch and backupEpochShare return the names of the entries of an
+// epoch of the history in a backup archive.
+func backupEpoch(n int) string {
+	return path.Join(GroupFolderName, HistoryFolderName, fmt.Sprintf("epoch-%d.toml", n))
+}
+
+func backupEpochShare(n int) string {
+	return path.Join(GroupFolderName, HistoryFolderName, fmt.Sprintf("epoch-%d", n)+privateExtension)
+}
+
+// Verify checks the consistency of the material of the backup: the private
+// key matches the public key, and the share, the distributed key and the
+// distributed key of the group all belong to the same distributed key.
+func (b *Backup) Verify() error {
+	if b.Pair == nil || b.Pair.Public == nil {
+		return errors.New("backup: no key pair")
+	}
+	if b.Pair.Key != nil {
+		g, err := keyGroup(b.Pair.Public.Key)
+		if err != nil {
+			return err
+		}
+		if !g.Point().Mul(b.Pair.Key, nil).Equal(b.Pair.Public.Key) {
+			return errors.New("backup: private key does not match the public key")
+		}
+	}
+	for i, e := range b.Epochs {
+		if e.Number != i+1 {
+			return errors.New("backup: epochs of the history are not numbered in order")
+		}
+		if e.Group == nil || e.Group.PublicKey == nil {
+			return fmt.Errorf("backup: epoch %d without distributed key", e.Number)
+		}
+		if e.Share != nil && !e.Share.Public().Equal(e.Group.PublicKey) {
+			return fmt.Errorf("backup: share of epoch %d does not match its distributed key", e.Number)
+		}
+	}
+	if b.Share == nil {
+		return nil
+	}
+	if b.Group == nil || b.Group.PublicKey == nil {
+		return errors.New("backup: share without the distributed key of the group")
+	}
+	if !b.Share.Public().Equal(b.Group.PublicKey) {
+		return errors.New("backup: share does not match the distributed key of the group")
+	}
+	if b.DistPublic != nil && !b.DistPublic.Equal(b.Group.PublicKey) {
+		return errors.New("backup: distributed key does not match the one of the group")
+	}
+	scheme := b.Group.Scheme
+	if scheme == nil {
+		scheme = DefaultScheme
+	}
+	public := scheme.PublicPoly(b.Share.Commits).Eval(b.Share.Share.I).V
+	if !scheme.KeyGroup.Point().Mul(b.Share.Share.V, nil).Equal(public) {
+		return errors.New("backup: share is not a share of the distributed key")
+	}
+	if idx, ok := b.Group.Index(b.Pair.Public); !ok || idx != b.Share.Share.I {
+		return errors.New("backup: key pair is not the holder of the share in the group")
+	}
+	return nil
+}
+
+// Write writes the backup to w as an archive encrypted with the key, and
+// returns its manifest.
+func (b *Backup) Write(w io.Writer, k *StoreKey) (*BackupManifest, error) {
+	if k == nil {
+		return nil, errors.New("backup: no key to encrypt the archive with")
+	}
+	files := make(map[string][]byte)
+	add := func(name string, t Tomler) error {
+		var buff bytes.Buffer
+		if err := toml.NewEncoder(&buff).Encode(t.TOML()); err != nil {
+			return err
+		}
+		files[name] = buff.Bytes()
+		return nil
+	}
+	if b.Pair.Key != nil {
+		if err := add(backupPrivateKey, b.Pair); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(backupPublicKey, b.Pair.Public); err != nil {
+		return nil, err
+	}
+	if b.Share != nil {
+		if err := add(backupShare, b.Share); err != nil {
+			return nil, err
+		}
+	}
+	if b.DistPublic != nil {
+		if err := add(backupDistKey, b.DistPublic); err != nil {
+			return nil, err
+		}
+	}
+	if b.Group != nil {
+		if err := add(backupGroup, b.Group); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range b.Epochs {
+		if err := add(backupEpoch(e.Number), e); err != nil {
+			return nil, err
+		}
+		if e.Share != nil {
+			if err := add(backupEpochShare(e.Number), e.Share); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if b.DB != nil {
+		files[backupDB] = b.DB
+	}
+
+	manifest := &BackupManifest{
+		Version: BackupVersion,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		Address: b.Pair.Public.Address(),
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hash := sha256.Sum256(files[name])
+		manifest.Files = append(manifest.Files, &BackupFile{
+			Name:   name,
+			Size:   len(files[name]),
+			SHA256: hex.EncodeToString(hash[:]),
+		})
+	}
+	var buff bytes.Buffer
+	if err := toml.NewEncoder(&buff).Encode(manifest); err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	entry := func(name string, content []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+	if err := entry(backupManifest, buff.Bytes()); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := entry(name, files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	e, err := k.encrypt(backupName, archive.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return manifest, toml.NewEncoder(w).Encode(&encryptedFile{e})
+}
+
+// ReadBackup decrypts the archive read from r with the key, checks the hashes
+// of its files against its manifest and decodes the backup. The consistency of
+// the material is checked by Verify.
+func ReadBackup(r io.Reader, k *StoreKey) (*Backup, *BackupManifest, error) {
+	var file encryptedFile
+	if _, err := toml.DecodeReader(r, &file); err != nil {
+		return nil, nil, err
+	}
+	if file.Encrypted == nil {
+		return nil, nil, errors.New("backup: not a backup archive")
+	}
+	if k == nil {
+		return nil, nil, ErrEncrypted
+	}
+	plain, err := k.decrypt(backupName, file.Encrypted)
+	if err != nil {
+		return nil, nil, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, nil, err
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[hdr.Name] = content
+	}
+
+	manifest := new(BackupManifest)
+	content, ok := files[backupManifest]
+	if !ok {
+		return nil, nil, errors.New("backup: archive without manifest")
+	}
+	if _, err := toml.Decode(string(content), manifest); err != nil {
+		return nil, nil, err
+	}
+	if manifest.Version != BackupVersion {
+		return nil, nil, fmt.Errorf("backup: unsupported archive version %d", manifest.Version)
+	}
+	if len(manifest.Files) != len(files)-1 {
+		return nil, nil, errors.New("backup: files of the archive do not match the manifest")
+	}
+	for _, f := range manifest.Files {
+		content, ok := files[f.Name]
+		if !ok {
+			return nil, nil, fmt.Errorf("backup: %s is missing from the archive", f.Name)
+		}
+		hash := sha256.Sum256(content)
+		if len(content) != f.Size || hex.EncodeToString(hash[:]) != f.SHA256 {
+			return nil, nil, fmt.Errorf("backup: %s does not match its hash", f.Name)
+		}
+	}
+
+	b := new(Backup)
+	decode := func(name string, t Tomler) (bool, error) {
+		content, ok := files[name]
+		if !ok {
+			return false, nil
+		}
+		tomlValue := t.TOMLValue()
+		if _, err := toml.Decode(string(content), tomlValue); err != nil {
+			return false, fmt.Errorf("backup: %s: %s", name, err)
+		}
+		if err := t.FromTOML(tomlValue); err != nil {
+			return false, fmt.Errorf("backup: %s: %s", name, err)
+		}
+		return true, nil
+	}
+	b.Pair = new(Pair)
+	if ok, err := decode(backupPrivateKey, b.Pair); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		b.Pair.Public = new(Identity)
+	}
+	if ok, err := decode(backupPublicKey, b.Pair.Public); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, errors.New("backup: archive without public key")
+	}
+	share, dist, group := new(Share), new(DistPublic), new(Group)
+	if ok, err := decode(backupShare, share); err != nil {
+		return nil, nil, err
+	} else if ok {
+		b.Share = share
+	}
+	if ok, err := decode(backupDistKey, dist); err != nil {
+		return nil, nil, err
+	} else if ok {
+		b.DistPublic = dist
+	}
+	if ok, err := decode(backupGroup, group); err != nil {
+		return nil, nil, err
+	} else if ok {
+		b.Group = group
+	}
+	for n := 1; ; n++ {
+		e := new(Epoch)
+		if ok, err := decode(backupEpoch(n), e); err != nil {
+			return nil, nil, err
+		} else if !ok {
+			break
+		}
+		share := new(Share)
+		if ok, err := decode(backupEpochShare(n), share); err != nil {
+			return nil, nil, err
+		} else if ok {
+			e.Share = share
+		}
+		b.Epochs = append(b.Epochs, e)
+	}
+	b.DB = files[backupDB]
+	return b, manifest, nil
+}
+
+// Restore saves the material of the backup in the store, after checking it
+// with Verify, so nothing is written from an inconsistent backup. The beacon
+// database is left to the caller.
+func (b *Backup) Restore(s Store) error {
+	if err := b.Verify(); err != nil {
+		return err
+	}
+	if err := s.SaveKeyPair(b.Pair); err != nil {
+		return err
+	}
+	if b.Group != nil {
+		if err := s.SaveGroup(b.Group); err != nil {
+			return err
+		}
+	}
+	if b.DistPublic != nil {
+		if err := s.SaveDistPublic(b.DistPublic); err != nil {
+			return err
+		}
+	}
+	if b.Share != nil {
+		if err := s.SaveShare(b.Share); err != nil {
+			return err
+		}
+	}
+	for _, e := range b.Epochs {
+		if err := s.SaveEpoch(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package key
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBackupRestore(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-key-backup")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+
+	n := 4
+	ps, group := BatchIdentities(n)
+	poly := share.NewPriPoly(G2, group.Threshold, nil, random.New())
+	_, commits := poly.Commit(G2.Point().Base()).Info()
+	idx, _ := group.Index(ps[0].Public)
+	s := &Share{Commits: commits, Share: poly.Shares(n)[idx]}
+	group.PublicKey = s.Public()
+
+	store := NewFileStore(path.Join(tmp, "node"))
+	require.NoError(t, store.SaveKeyPair(ps[0]))
+	require.NoError(t, store.SaveGroup(group))
+	require.NoError(t, store.SaveShare(s))
+	require.NoError(t, store.SaveDistPublic(s.Public()))
+	// the history is backed up, with the shares the store kept
+	require.NoError(t, store.SaveEpoch(&Epoch{Number: 1, Group: group}))
+	require.NoError(t, store.SaveEpoch(&Epoch{Number: 2, FromRound: 10, Group: group, Share: s}))
+
+	b, err := NewBackup(store)
+	require.NoError(t, err)
+	require.NoError(t, b.Verify())
+	b.DB = []byte("beacons")
+	pass, err := NewPassphraseKey([]byte("backup passphrase"))
+	require.NoError(t, err)
+	var archive bytes.Buffer
+	manifest, err := b.Write(&archive, pass)
+	require.NoError(t, err)
+	require.Len(t, manifest.Files, 9)
+	require.NotContains(t, archive.String(), ScalarToString(ps[0].Key))
+
+	wrong, err := NewPassphraseKey([]byte("wrong"))
+	require.NoError(t, err)
+	_, _, err = ReadBackup(bytes.NewReader(archive.Bytes()), wrong)
+	require.Equal(t, ErrWrongKey, err)
+
+	read, readManifest, err := ReadBackup(bytes.NewReader(archive.Bytes()), pass)
+	require.NoError(t, err)
+	require.Equal(t, manifest.Hash(), readManifest.Hash())
+	require.Equal(t, []byte("beacons"), read.DB)
+
+	restored := NewFileStore(path.Join(tmp, "restored"))
+	require.NoError(t, read.Restore(restored))
+	pair, err := restored.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, ps[0].Key.String(), pair.Key.String())
+	loaded, err := restored.LoadShare()
+	require.NoError(t, err)
+	require.Equal(t, s.Share.V.String(), loaded.Share.V.String())
+	epochs, err := restored.LoadEpochs()
+	require.NoError(t, err)
+	require.Len(t, epochs, 2)
+	require.Equal(t, uint64(10), epochs[1].FromRound)
+	_, err = restored.LoadEpochShare(1)
+	require.True(t, os.IsNotExist(err))
+	epochShare, err := restored.LoadEpochShare(2)
+	require.NoError(t, err)
+	require.Equal(t, s.Share.V.String(), epochShare.Share.V.String())
+
+	// a share of another distributed key is refused before writing anything
+	other := share.NewPriPoly(G2, group.Threshold, nil, random.New())
+	_, otherCommits := other.Commit(G2.Point().Base()).Info()
+	read.Share = &Share{Commits: otherCommits, Share: other.Shares(n)[idx]}
+	empty := NewFileStore(path.Join(tmp, "empty"))
+	require.Error(t, read.Restore(empty))
+	_, err = empty.LoadKeyPair()
+	require.True(t, os.IsNotExist(err))
+
+	// a share that does not evaluate to the distributed key is refused, as a
+	// share held by another node
+	read.Share = &Share{Commits: commits, Share: &share.PriShare{I: idx, V: G2.Scalar().Pick(random.New())}}
+	require.Error(t, read.Verify())
+	read.Share = &Share{Commits: commits, Share: poly.Shares(n)[(idx+1)%n]}
+	require.Error(t, read.Verify())
+}
@@ -0,0 +1,280 @@
+package key
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dedis/drand/fs"
+	"github.com/nikkolasg/slog"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrEncrypted is returned when loading an encrypted file from a store that
+// has not been given the passphrase or key file unlocking it.
+var ErrEncrypted = errors.New("store: private file is encrypted, a passphrase or key file is needed")
+
+// ErrWrongKey is returned when an encrypted file can not be decrypted with the
+// key of the store, the passphrase or key file being wrong.
+var ErrWrongKey = errors.New("store: can't decrypt private file, wrong passphrase or key file")
+
+// Parameters of the scrypt key derivation of passphrases: N=2^15, r=8 and p=1
+// take about 100ms and 32MB of memory, as recommended for interactive logins.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+const (
+	kdfScrypt  = "scrypt"
+	kdfKeyFile = "key-file"
+	saltSize   = 16
+	// minimum length of the content of a key file
+	minKeyFileSize = 32
+)
+
+// StoreKey unlocks the private files of an encrypted store, the long-term key
+// pair and the distributed share. It derives, for each file, an AES-256-GCM key
+// from either a passphrase, with the memory-hard scrypt function, or the
+// content of a key file.
+type StoreKey struct {
+	kdf    string
+	secret []byte
+}
+
+// NewPassphraseKey returns a StoreKey deriving the keys from the passphrase.
+func NewPassphraseKey(passphrase []byte) (*StoreKey, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("store: empty passphrase")
+	}
+	return &StoreKey{kdf: kdfScrypt, secret: passphrase}, nil
+}
+
+// NewKeyFileKey returns a StoreKey deriving the keys from the content of the
+// given file, which must hold at least 32 random bytes.
+func NewKeyFileKey(path string) (*StoreKey, error) {
+	secret, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) < minKeyFileSize {
+		return nil, fmt.Errorf("store: key file must hold at least %d bytes", minKeyFileSize)
+	}
+	return &StoreKey{kdf: kdfKeyFile, secret: secret}, nil
+}
+
+// EncryptedTOML is the TOML-able content of an encrypted file. The
+// ciphertext is the encryption of the TOML encoding of the private material,
+// authenticated alongside the name of the file so files can not be swapped.
+type EncryptedTOML struct {
+	KDF        string
+	Salt       string
+	N          int `toml:",omitempty"`
+	R          int `toml:",omitempty"`
+	P          int `toml:",omitempty"`
+	Nonce      string
+	Ciphertext string
+}
+
+// encryptedFile is the layout of encrypted files. Plaintext files have no
+// Encrypted table, which tells them apart.
+type encryptedFile struct {
+	Encrypted *EncryptedTOML
+}
+
+// derive returns the AES key of the file encrypted with the given parameters.
+func (k *StoreKey) derive(e *EncryptedTOML) ([]byte, error) {
+	salt, err := hex.DecodeString(e.Salt)
+	if err != nil {
+		return nil, err
+	}
+	switch e.KDF {
+	case kdfScrypt:
+		return scrypt.Key(k.secret, salt, e.N, e.R, e.P, 32)
+	case kdfKeyFile:
+		h := sha256.New()
+		h.Write(salt)
+		h.Write(k.secret)
+		return h.Sum(nil), nil
+	default:
+		return nil, fmt.Errorf("store: unknown key derivation %q", e.KDF)
+	}
+}
+
+func (k *StoreKey) aead(e *EncryptedTOML) (cipher.AEAD, error) {
+	if e.KDF != k.kdf {
+		return nil, fmt.Errorf("store: file encrypted with a %s, not a %s", e.KDF, k.kdf)
+	}
+	key, err := k.derive(e)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (k *StoreKey) encrypt(name string, plain []byte) (*EncryptedTOML, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	e := &EncryptedTOML{KDF: k.kdf, Salt: hex.EncodeToString(salt)}
+	if k.kdf == kdfScrypt {
+		e.N, e.R, e.P = scryptN, scryptR, scryptP
+	}
+	aead, err := k.aead(e)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	e.Nonce = hex.EncodeToString(nonce)
+	e.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, plain, []byte(name)))
+	return e, nil
+}
+
+func (k *StoreKey) decrypt(name string, e *EncryptedTOML) ([]byte, error) {
+	aead, err := k.aead(e)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(e.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("store: invalid nonce")
+	}
+	ciphertext, err := hex.DecodeString(e.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// NewEncryptedFileStore returns a file store encrypting the long-term key pair
+// and the distributed share with the given key. Public files, the group and
+// the distributed key, are stored as in a plaintext store. Private files
+// still in plaintext are loaded, with a warning, until they are saved again
+// or converted with Reencrypt.
+func NewEncryptedFileStore(baseFolder string, k *StoreKey) Store {
+	store := NewFileStore(baseFolder).(*fileStore)
+	store.key = k
+	return store
+}
+
+// savePrivate saves private material, encrypted if the store has a key. The
+// encrypted file is written next to the destination and renamed, so a crash
+// never leaves a truncated key on disk.
+func (f *fileStore) savePrivate(path string, t Tomler) error {
+	if f.key == nil {
+		return Save(path, t, true)
+	}
+	var buff bytes.Buffer
+	if err := toml.NewEncoder(&buff).Encode(t.TOML()); err != nil {
+		return err
+	}
+	e, err := f.key.encrypt(filepath.Base(path), buff.Bytes())
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	fd, err := fs.CreateSecureFile(tmp)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(fd).Encode(&encryptedFile{e}); err != nil {
+		fd.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadPrivate loads private material, decrypting it if it is encrypted.
+func (f *fileStore) loadPrivate(path string, t Tomler) error {
+	var file encryptedFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return err
+	}
+	if file.Encrypted == nil {
+		if f.key != nil {
+			slog.Infof("store: %s is not encrypted, run `drand key reencrypt` to encrypt it", path)
+		}
+		return Load(path, t)
+	}
+	if f.key == nil {
+		return ErrEncrypted
+	}
+	plain, err := f.key.decrypt(filepath.Base(path), file.Encrypted)
+	if err != nil {
+		return err
+	}
+	tomlValue := t.TOMLValue()
+	if _, err := toml.Decode(string(plain), tomlValue); err != nil {
+		return err
+	}
+	return t.FromTOML(tomlValue)
+}
+
+// Reencrypt encrypts the private files of the store in the given folder with
+// the key to. The files are decrypted with the key from, or read as plaintext
+// if from is nil. A missing share, before the first DKG, and a missing next key
+// pair, out of a key rotation, are skipped.
+func Reencrypt(baseFolder string, from, to *StoreKey) error {
+	if to == nil {
+		return errors.New("store: no key to encrypt with")
+	}
+	old := NewFileStore(baseFolder).(*fileStore)
+	old.key = from
+	pair, err := old.LoadKeyPair()
+	if err != nil {
+		return err
+	}
+	share, err := old.LoadShare()
+	hasShare := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	next, err := old.LoadNextKeyPair()
+	hasNext := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	encrypted := NewEncryptedFileStore(baseFolder, to).(*fileStore)
+	if err := encrypted.savePrivate(encrypted.privateKeyFile, pair); err != nil {
+		return err
+	}
+	if hasNext {
+		if err := encrypted.savePrivate(encrypted.nextPrivateKey, next); err != nil {
+			return err
+		}
+	}
+	if !hasShare {
+		return nil
+	}
+	return encrypted.savePrivate(encrypted.shareFile, share)
+}
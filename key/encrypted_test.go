@@ -0,0 +1,66 @@
+package key
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStore(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-key-encrypted")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	ps, _ := BatchIdentities(1)
+	pair := ps[0]
+
+	// plaintext keys are converted with Reencrypt
+	plain := NewFileStore(tmp).(*fileStore)
+	require.NoError(t, plain.SaveKeyPair(pair))
+	pass, err := NewPassphraseKey([]byte("correct horse battery staple"))
+	require.NoError(t, err)
+	require.NoError(t, Reencrypt(tmp, nil, pass))
+
+	content, err := ioutil.ReadFile(plain.privateKeyFile)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(content), "Ciphertext"))
+	require.False(t, strings.Contains(string(content), ScalarToString(pair.Key)))
+	_, err = plain.LoadKeyPair()
+	require.Equal(t, ErrEncrypted, err)
+
+	store := NewEncryptedFileStore(tmp, pass)
+	loaded, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, pair.Key.String(), loaded.Key.String())
+	require.Equal(t, pair.Public.Address(), loaded.Public.Address())
+
+	wrong, err := NewPassphraseKey([]byte("wrong"))
+	require.NoError(t, err)
+	_, err = NewEncryptedFileStore(tmp, wrong).LoadKeyPair()
+	require.Equal(t, ErrWrongKey, err)
+
+	// from a passphrase to a key file
+	keyFile := path.Join(tmp, "store.key")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(strings.Repeat("k", minKeyFileSize)), 0600))
+	file, err := NewKeyFileKey(keyFile)
+	require.NoError(t, err)
+	require.NoError(t, Reencrypt(tmp, pass, file))
+	_, err = NewEncryptedFileStore(tmp, pass).LoadKeyPair()
+	require.Error(t, err)
+	store = NewEncryptedFileStore(tmp, file)
+	loaded, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, pair.Key.String(), loaded.Key.String())
+
+	// a file can not be passed for another one
+	require.NoError(t, os.Rename(plain.privateKeyFile, plain.shareFile))
+	_, err = store.LoadShare()
+	require.Equal(t, ErrWrongKey, err)
+
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("short"), 0600))
+	_, err = NewKeyFileKey(keyFile)
+	require.Error(t, err)
+}
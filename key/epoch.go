@@ -0,0 +1,142 @@
+package key
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/dedis/drand/fs"
+	"github.com/nikkolasg/slog"
+)
+
+// HistoryFolderName is the folder, under the groups folder, keeping the
+// history of the epochs of the node.
+const HistoryFolderName = "history"
+
+// Epoch is one configuration of the node in its history: the group and its
+// distributed key, as resulting from a DKG or a resharing, and the first round
+// produced with them. Epochs are numbered by the node from 1, in the order of
+// its DKG and resharings, so beacons signed before a resharing can be linked
+// to the key and the group that produced them. The numbers are local to the
+// node: a node joining in a resharing starts its history there. The first
+// round identifies the epoch across the nodes.
+type Epoch struct {
+	Number int
+	// FromRound is the first round of the epoch. For the groups with a
+	// genesis time, it is the first round of the group, see
+	// Group.FirstRound, the same on all the nodes. For the older groups, it
+	// is the round following the last beacon of the node, 0 if the node did
+	// not produce any beacon before it.
+	FromRound uint64
+	// Group holds the distributed key of the epoch.
+	Group *Group
+	// Share is the share of the node in the epoch. It is nil when the store
+	// does not keep the shares of past epochs.
+	Share *Share
+}
+
+// DistPublic returns the distributed key of the epoch.
+func (e *Epoch) DistPublic() *DistPublic {
+	return e.Group.PublicKey
+}
+
+// EpochTOML is the TOML representation of an Epoch, without its share which
+// is saved in a private file.
+type EpochTOML struct {
+	Number    int
+	FromRound uint64
+	Group     *GroupTOML
+}
+
+// TOML returns a TOML-compatible version of the epoch.
+func (e *Epoch) TOML() interface{} {
+	return &EpochTOML{
+		Number:    e.Number,
+		FromRound: e.FromRound,
+		Group:     e.Group.TOML().(*GroupTOML),
+	}
+}
+
+// FromTOML initializes the epoch from its TOML-compatible version.
+func (e *Epoch) FromTOML(i interface{}) error {
+	etoml, ok := i.(*EpochTOML)
+	if !ok {
+		return errors.New("epoch: expected EpochTOML")
+	}
+	if etoml.Group == nil {
+		return errors.New("epoch: no group")
+	}
+	e.Number = etoml.Number
+	e.FromRound = etoml.FromRound
+	e.Group = new(Group)
+	return e.Group.FromTOML(etoml.Group)
+}
+
+// TOMLValue returns an empty TOML-compatible value of an epoch.
+func (e *Epoch) TOMLValue() interface{} {
+	return &EpochTOML{}
+}
+
+// EpochAt returns the epoch of the history, ordered by number, in which the
+// given round was produced, or nil if the round predates the history.
+func EpochAt(epochs []*Epoch, round uint64) *Epoch {
+	var found *Epoch
+	for _, e := range epochs {
+		if e.FromRound > round {
+			break
+		}
+		found = e
+	}
+	return found
+}
+
+func (f *fileStore) epochFile(n int) string {
+	return path.Join(f.historyFolder, fmt.Sprintf("epoch-%d.toml", n))
+}
+
+func (f *fileStore) epochShareFile(n int) string {
+	return path.Join(f.historyFolder, fmt.Sprintf("epoch-%d", n)) + privateExtension
+}
+
+// SaveEpoch adds the epoch to the history of the store, with the share of the
+// node if it is set.
+func (f *fileStore) SaveEpoch(e *Epoch) error {
+	if e.Number < 1 {
+		return errors.New("store: epochs are numbered from 1")
+	}
+	if e.Group == nil || e.Group.PublicKey == nil {
+		return errors.New("store: epoch without distributed key")
+	}
+	fs.CreateSecureFolder(f.historyFolder)
+	slog.Infof("crypto store: saving epoch %d from round %d in %s", e.Number, e.FromRound, f.historyFolder)
+	if e.Share != nil {
+		if err := f.savePrivate(f.epochShareFile(e.Number), e.Share); err != nil {
+			return err
+		}
+	}
+	return Save(f.epochFile(e.Number), e, false)
+}
+
+// LoadEpochs returns the history of the store, ordered by epoch number, without
+// the shares of the node. The history is empty before the first DKG.
+func (f *fileStore) LoadEpochs() ([]*Epoch, error) {
+	var epochs []*Epoch
+	for n := 1; ; n++ {
+		e := new(Epoch)
+		err := Load(f.epochFile(n), e)
+		if os.IsNotExist(err) {
+			return epochs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		epochs = append(epochs, e)
+	}
+}
+
+// LoadEpochShare returns the share of the node in the given epoch, if the
+// store has kept it.
+func (f *fileStore) LoadEpochShare(n int) (*Share, error) {
+	s := new(Share)
+	return s, f.loadPrivate(f.epochShareFile(n), s)
+}
@@ -0,0 +1,59 @@
+package key
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestEpochHistory(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-key-epoch")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp)
+
+	epochs, err := store.LoadEpochs()
+	require.NoError(t, err)
+	require.Len(t, epochs, 0)
+	require.Nil(t, EpochAt(epochs, 10))
+
+	n := 4
+	_, group := BatchIdentities(n)
+	poly := share.NewPriPoly(G2, group.Threshold, nil, random.New())
+	_, commits := poly.Commit(G2.Point().Base()).Info()
+	first := *group
+	first.PublicKey = &DistPublic{commits}
+	s := &Share{Commits: commits, Share: poly.Shares(n)[0]}
+	require.NoError(t, store.SaveEpoch(&Epoch{Number: 1, Group: &first, Share: s}))
+
+	// a resharing keeps the distributed key but changes the commitments
+	second := *group
+	second.Threshold = group.Threshold + 1
+	second.PublicKey = &DistPublic{[]kyber.Point{commits[0], G2.Point().Pick(random.New())}}
+	require.NoError(t, store.SaveEpoch(&Epoch{Number: 2, FromRound: 100, Group: &second}))
+	require.Error(t, store.SaveEpoch(&Epoch{Number: 0, Group: &second}))
+
+	epochs, err = store.LoadEpochs()
+	require.NoError(t, err)
+	require.Len(t, epochs, 2)
+	require.Equal(t, 1, epochs[0].Number)
+	require.Equal(t, uint64(100), epochs[1].FromRound)
+	require.Equal(t, second.Threshold, epochs[1].Group.Threshold)
+	require.True(t, epochs[1].DistPublic().Equal(second.PublicKey))
+
+	require.Equal(t, 1, EpochAt(epochs, 0).Number)
+	require.Equal(t, 1, EpochAt(epochs, 99).Number)
+	require.Equal(t, 2, EpochAt(epochs, 100).Number)
+	require.Equal(t, 2, EpochAt(epochs, 1000).Number)
+
+	loaded, err := store.LoadEpochShare(1)
+	require.NoError(t, err)
+	require.Equal(t, s.Share.V.String(), loaded.Share.V.String())
+	_, err = store.LoadEpochShare(2)
+	require.True(t, os.IsNotExist(err))
+}
@@ -27,6 +27,24 @@ type Group struct {
 	Threshold int
 	// Period to use for the beacon randomness generation
 	Period time.Duration
+	// Unchained indicates that the beacons of this group sign the round number
+	// only, instead of the round number and the previous randomness. Each
+	// beacon can then be verified on its own.
+	Unchained bool
+	// Scheme is the signature scheme used by the beacons of this group. It
+	// determines the group of the keys of the nodes.
+	Scheme *Scheme
+	// GenesisTime is the time, in seconds since the UNIX epoch, at which the
+	// first round is due. Round r is due at GenesisTime + (r-1) * Period, so
+	// anybody holding the group knows the round due at a given time. It is 0
+	// for the groups created before it was introduced, whose rounds are not
+	// tied to time.
+	GenesisTime int64
+	// TransitionTime is the time, in seconds since the UNIX epoch, from which
+	// the group produced by a resharing takes over from the previous one: its
+	// first round is the first round due at or after that time. It is 0 for
+	// the groups of a fresh DKG, which start at the genesis time.
+	TransitionTime int64
 }
 
 // Identities return the underlying slice of identities
@@ -64,27 +82,128 @@ func (g *Group) Public(i int) *Identity {
 	return g.Nodes[i]
 }
 
-// Hash returns an unique short representation of this group.
+// GroupHashVersion is the version of the encoding hashed by Hash. It is
+// incremented each time the hashed information changes, which changes the
+// hash of all the groups: nodes must then run the same version to agree on a
+// group.
+const GroupHashVersion = 1
+
+// Hash returns an unique short representation of this group: its nodes, with
+// their address, TLS settings and metadata, its threshold, beacon mode, times
+// and scheme.
 // NOTE: It currently does NOT take into account the distributed public key when
 // set for simplicity (we want old nodes and new nodes to easily refer to the
-// same group for example). This may cause trouble in the future and may require
-// more thoughts.
+// same group for example). Clients verifying beacons should pin the ChainHash
+// instead, which covers it.
 func (g *Group) Hash() (string, error) {
+	scheme := g.Scheme
+	if scheme == nil {
+		scheme = DefaultScheme
+	}
 	h := blake2b.New256()
+	h.Write([]byte("drand-group"))
+	binary.Write(h, binary.LittleEndian, uint32(GroupHashVersion))
 
-	// all nodes public keys and positions
+	// all nodes and their positions
 	for i, n := range g.Nodes {
 		binary.Write(h, binary.LittleEndian, uint32(i))
-		b, err := n.Key.MarshalBinary()
+		msg, err := n.signedMessage()
 		if err != nil {
 			return "", err
 		}
-		h.Write(b)
+		binary.Write(h, binary.LittleEndian, uint32(len(msg)))
+		h.Write(msg)
 	}
 	binary.Write(h, binary.LittleEndian, uint32(g.Threshold))
+	if g.Unchained {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	binary.Write(h, binary.LittleEndian, g.GenesisTime)
+	binary.Write(h, binary.LittleEndian, g.TransitionTime)
+	for _, f := range []string{scheme.Name, scheme.KeyGroupName()} {
+		binary.Write(h, binary.LittleEndian, uint32(len(f)))
+		h.Write([]byte(f))
+	}
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+// ChainHashVersion is the version of the encoding hashed by ChainHash. It is
+// incremented each time the hashed information changes.
+const ChainHashVersion = 1
+
+// ChainHash returns an unique short representation of the beacon chain of this
+// group: everything a client needs to verify its beacons and their times, that
+// is the collective key, the period, the genesis time, the beacon mode, the
+// scheme and the genesis message signed with the first beacon. Unlike Hash, it
+// does not cover the nodes and threshold, so it stays the same across
+// resharings. The group must have ran a DKG.
+func (g *Group) ChainHash(genesis []byte) (string, error) {
+	if g.PublicKey == nil || len(g.PublicKey.Coefficients) == 0 {
+		return "", errors.New("group: no distributed key")
+	}
+	scheme := g.Scheme
+	if scheme == nil {
+		scheme = DefaultScheme
+	}
+	h := blake2b.New256()
+	h.Write([]byte("drand-chain"))
+	binary.Write(h, binary.LittleEndian, uint32(ChainHashVersion))
+	b, err := g.PublicKey.Key().MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	h.Write(b)
+	binary.Write(h, binary.LittleEndian, int64(g.Period))
+	binary.Write(h, binary.LittleEndian, g.GenesisTime)
+	if g.Unchained {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+	for _, f := range []string{scheme.Name, scheme.KeyGroupName(), string(genesis)} {
+		binary.Write(h, binary.LittleEndian, uint32(len(f)))
+		h.Write([]byte(f))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// TimeOfRound returns the time at which the given round is due. The group must
+// have a genesis time.
+func (g *Group) TimeOfRound(round uint64) time.Time {
+	genesis := time.Unix(g.GenesisTime, 0)
+	if round <= 1 {
+		return genesis
+	}
+	return genesis.Add(time.Duration(round-1) * g.Period)
+}
+
+// FirstRound returns the first round produced by the group: the round due at
+// its transition time for the group of a resharing, and 1 otherwise. The group
+// must have a genesis time.
+func (g *Group) FirstRound() uint64 {
+	if g.TransitionTime == 0 {
+		return 1
+	}
+	return g.RoundAt(time.Unix(g.TransitionTime, 0))
+}
+
+// RoundAt returns the first round due at or after the given time, that is 1
+// for any time up to the genesis time. The group must have a genesis time.
+func (g *Group) RoundAt(t time.Time) uint64 {
+	genesis := time.Unix(g.GenesisTime, 0)
+	if !t.After(genesis) || g.Period <= 0 {
+		return 1
+	}
+	elapsed := t.Sub(genesis)
+	round := uint64(elapsed/g.Period) + 1
+	if elapsed%g.Period != 0 {
+		round++
+	}
+	return round
+}
+
 // Points returns itself under the form of a list of kyber.Point
 func (g *Group) Points() []kyber.Point {
 	pts := make([]kyber.Point, g.Len())
@@ -111,6 +230,12 @@ type GroupTOML struct {
 	PublicKey *DistPublicTOML
 	Threshold int
 	Period    string
+	Unchained bool
+	Scheme    string
+	KeyGroup  string
+	// GenesisTime and TransitionTime are in seconds since the UNIX epoch
+	GenesisTime    int64
+	TransitionTime int64
 }
 
 // FromTOML decodes the group from the toml struct
@@ -119,6 +244,9 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 	if !ok {
 		return fmt.Errorf("grouptoml unknown")
 	}
+	if g.Scheme, err = SchemeFromName(gt.Scheme, gt.KeyGroup); err != nil {
+		return fmt.Errorf("group: %v", err)
+	}
 	g.Threshold = gt.Threshold
 	g.Nodes = make([]*Identity, len(gt.Nodes))
 	for i, ptoml := range gt.Nodes {
@@ -126,6 +254,9 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 		if err := g.Nodes[i].FromTOML(ptoml); err != nil {
 			return fmt.Errorf("group: unwrapping node[%d]: %v", i, err)
 		}
+		if !InGroup(g.Nodes[i].Key, g.Scheme.KeyGroup) {
+			return fmt.Errorf("group: node[%d] key is not on %s", i, g.Scheme.KeyGroupName())
+		}
 	}
 
 	if g.Threshold < vss.MinimumT(len(gt.Nodes)) {
@@ -140,7 +271,15 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 		if err = g.PublicKey.FromTOML(gt.PublicKey); err != nil {
 			return fmt.Errorf("group: unwrapping distributed public key: %v", err)
 		}
+		for _, c := range g.PublicKey.Coefficients {
+			if !InGroup(c, g.Scheme.KeyGroup) {
+				return fmt.Errorf("group: distributed public key is not on %s", g.Scheme.KeyGroupName())
+			}
+		}
 	}
+	g.Unchained = gt.Unchained
+	g.GenesisTime = gt.GenesisTime
+	g.TransitionTime = gt.TransitionTime
 	g.Period, err = time.ParseDuration(gt.Period)
 	return err
 }
@@ -157,6 +296,15 @@ func (g *Group) TOML() interface{} {
 		gtoml.PublicKey = g.PublicKey.TOML().(*DistPublicTOML)
 	}
 	gtoml.Period = g.Period.String()
+	gtoml.Unchained = g.Unchained
+	gtoml.GenesisTime = g.GenesisTime
+	gtoml.TransitionTime = g.TransitionTime
+	scheme := g.Scheme
+	if scheme == nil {
+		scheme = DefaultScheme
+	}
+	gtoml.Scheme = scheme.Name
+	gtoml.KeyGroup = scheme.KeyGroupName()
 	return gtoml
 }
 
@@ -167,17 +315,46 @@ func (g *Group) TOMLValue() interface{} {
 
 // MergeGroup returns a NEW group with both list of identities combined,
 // the maximum between the default threshold and the group's threshold,
-// and with the same period as the group.
+// and with the same period, genesis time, beacon mode and scheme as the group.
 func (g *Group) MergeGroup(list []*Identity) *Group {
 	thr := DefaultThreshold(len(list) + g.Len())
 	if thr < g.Threshold {
 		thr = g.Threshold
 	}
 	return &Group{
-		Nodes:     append(g.Identities(), list...),
-		Threshold: thr,
-		Period:    g.Period,
+		Nodes:       append(g.Identities(), list...),
+		Threshold:   thr,
+		Period:      g.Period,
+		GenesisTime: g.GenesisTime,
+		Unchained:   g.Unchained,
+		Scheme:      g.Scheme,
+	}
+}
+
+// ReplaceNode returns a NEW group where the node of the identity old is
+// replaced by the identity new, at the same position, and with the same
+// threshold, period, genesis time, beacon mode, scheme and distributed key. It is the new
+// group of a resharing rotating the key of that node.
+func (g *Group) ReplaceNode(old, new *Identity) (*Group, error) {
+	idx, found := g.Index(old)
+	if !found {
+		return nil, errors.New("group: identity to replace not in the group")
 	}
+	if g.Contains(new) {
+		return nil, errors.New("group: new identity already in the group")
+	}
+	nodes := make([]*Identity, g.Len())
+	copy(nodes, g.Nodes)
+	nodes[idx] = new
+	return &Group{
+		Nodes:       nodes,
+		PublicKey:   g.PublicKey,
+		Threshold:   g.Threshold,
+		Period:      g.Period,
+		GenesisTime: g.GenesisTime,
+		Unchained:   g.Unchained,
+		Scheme:      g.Scheme,
+	}, nil
 }
 
 // NewGroup returns a list of identities as a Group.
@@ -185,6 +362,7 @@ func NewGroup(list []*Identity, threshold int) *Group {
 	return &Group{
 		Nodes:     list,
 		Threshold: threshold,
+		Scheme:    DefaultScheme,
 	}
 }
 
@@ -194,5 +372,6 @@ func LoadGroup(list []*Identity, public *DistPublic, threshold int) *Group {
 		Nodes:     list,
 		Threshold: threshold,
 		PublicKey: public,
+		Scheme:    DefaultScheme,
 	}
 }
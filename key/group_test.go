@@ -45,4 +45,206 @@ func TestGroupSaveLoad(t *testing.T) {
 	require.Equal(t, loaded.Threshold, group.Threshold)
 	require.True(t, loaded.PublicKey.Equal(group.PublicKey))
 	require.Equal(t, loaded.Period, group.Period)
+	require.False(t, loaded.Unchained)
+
+	h1, err := group.Hash()
+	require.NoError(t, err)
+	group.Unchained = true
+	h2, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h2)
+
+	require.NoError(t, Save(groupPath, group, false))
+	loaded = &Group{}
+	require.NoError(t, Load(groupPath, loaded))
+	require.True(t, loaded.Unchained)
+	require.True(t, loaded.MergeGroup(nil).Unchained)
+	require.Equal(t, DefaultScheme.Name, loaded.Scheme.Name)
+
+	group.Scheme = SchemeFromNameOrPanic(SchemeRFC9380BN254, KeyGroupG2)
+	h3, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h2, h3)
+	require.NoError(t, Save(groupPath, group, false))
+	loaded = &Group{}
+	require.NoError(t, Load(groupPath, loaded))
+	require.Equal(t, SchemeRFC9380BN254, loaded.Scheme.Name)
+	require.Equal(t, SchemeRFC9380BN254, loaded.MergeGroup(nil).Scheme.Name)
+
+	// keys on G2 can not be loaded in a group with keys on G1
+	gtoml = group.TOML().(*GroupTOML)
+	gtoml.KeyGroup = KeyGroupG1
+	require.Error(t, (&Group{}).FromTOML(gtoml))
+}
+
+func TestGroupKeysOnG1(t *testing.T) {
+	n := 3
+	ids := make([]*Identity, n)
+	for i := 0; i < n; i++ {
+		ids[i] = NewKeyPairIn(G1, "--").Public
+	}
+	group := NewGroup(ids, DefaultThreshold(n))
+	group.Scheme = SchemeFromNameOrPanic(SchemeRFC9380BN254, KeyGroupG1)
+	group.PublicKey = &DistPublic{[]kyber.Point{ids[0].Key}}
+
+	loaded := &Group{}
+	require.NoError(t, loaded.FromTOML(group.TOML()))
+	require.Equal(t, KeyGroupG1, loaded.Scheme.KeyGroupName())
+	require.True(t, loaded.PublicKey.Equal(group.PublicKey))
+	for i := range ids {
+		require.True(t, InGroup(loaded.Nodes[i].Key, G1))
+		require.True(t, loaded.Nodes[i].Equal(ids[i]))
+	}
+}
+
+func TestGroupReplaceNode(t *testing.T) {
+	n := 5
+	ps, group := BatchIdentities(n)
+	group.Period = 3 * time.Second
+	group.GenesisTime = 1000
+	group.PublicKey = &DistPublic{[]kyber.Point{ps[0].Public.Key}}
+	next := NewKeyPair(ps[2].Public.Addr)
+
+	replaced, err := group.ReplaceNode(ps[2].Public, next.Public)
+	require.NoError(t, err)
+	oldIdx, _ := group.Index(ps[2].Public)
+	idx, found := replaced.Index(next.Public)
+	require.True(t, found)
+	require.Equal(t, oldIdx, idx)
+	require.False(t, replaced.Contains(ps[2].Public))
+	require.True(t, group.Contains(ps[2].Public))
+	require.Equal(t, group.Threshold, replaced.Threshold)
+	require.Equal(t, group.Period, replaced.Period)
+	require.Equal(t, group.GenesisTime, replaced.GenesisTime)
+	require.True(t, replaced.PublicKey.Equal(group.PublicKey))
+
+	_, err = group.ReplaceNode(next.Public, ps[2].Public)
+	require.Error(t, err)
+	_, err = group.ReplaceNode(ps[2].Public, ps[3].Public)
+	require.Error(t, err)
+}
+
+func TestGroupHashSignedNodes(t *testing.T) {
+	ps, group := BatchIdentities(3)
+	h1, err := group.Hash()
+	require.NoError(t, err)
+	// the metadata of unsigned nodes is part of the hash
+	group.Nodes[0].Name = "node"
+	h2, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h2)
+	group.Nodes[1].Addr = "127.0.0.1:1"
+	h3, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h2, h3)
+
+	// the nodes of the group are the public identities of the pairs
+	require.NoError(t, ps[0].SelfSign())
+	h4, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h3, h4)
+	ps[0].Public.Addr = "127.0.0.1:2"
+	h5, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h4, h5)
+}
+
+func TestGroupChainHash(t *testing.T) {
+	ps, group := BatchIdentities(4)
+	group.Period = 30 * time.Second
+	genesis := []byte("genesis")
+	h1, err := group.ChainHash(genesis)
+	require.NoError(t, err)
+
+	// a resharing keeps the chain hash
+	reshared := *group
+	reshared.Nodes = group.Nodes[1:]
+	reshared.Threshold = group.Threshold - 1
+	reshared.PublicKey = &DistPublic{[]kyber.Point{group.PublicKey.Key(), ps[0].Public.Key}}
+	h2, err := reshared.ChainHash(genesis)
+	require.NoError(t, err)
+	require.Equal(t, h1, h2)
+
+	// but anything a client needs to verify beacons changes it
+	other := *group
+	other.Period = time.Minute
+	h, err := other.ChainHash(genesis)
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h)
+	other = *group
+	other.PublicKey = &DistPublic{[]kyber.Point{ps[1].Public.Key}}
+	h, err = other.ChainHash(genesis)
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h)
+	other = *group
+	other.Unchained = true
+	h, err = other.ChainHash(genesis)
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h)
+	other = *group
+	other.GenesisTime = 1000
+	h, err = other.ChainHash(genesis)
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h)
+	h, err = group.ChainHash([]byte("another genesis"))
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h)
+
+	// the group hash ignores the period and the distributed key
+	gh1, err := group.Hash()
+	require.NoError(t, err)
+	other = *group
+	other.Period = time.Minute
+	other.PublicKey = nil
+	gh2, err := other.Hash()
+	require.NoError(t, err)
+	require.Equal(t, gh1, gh2)
+	_, err = other.ChainHash(genesis)
+	require.Error(t, err)
+}
+
+func TestGroupRoundAt(t *testing.T) {
+	_, group := BatchIdentities(4)
+	group.Period = 30 * time.Second
+	group.GenesisTime = 1000
+
+	require.Equal(t, time.Unix(1000, 0), group.TimeOfRound(1))
+	require.Equal(t, time.Unix(1030, 0), group.TimeOfRound(2))
+	require.Equal(t, time.Unix(1300, 0), group.TimeOfRound(11))
+
+	for _, c := range []struct {
+		time  int64
+		round uint64
+	}{
+		{0, 1},
+		{1000, 1},
+		{1001, 2},
+		{1030, 2},
+		{1031, 3},
+		{1300, 11},
+	} {
+		require.Equal(t, c.round, group.RoundAt(time.Unix(c.time, 0)), "time %d", c.time)
+		// the round is always due at or after the time
+		require.False(t, group.TimeOfRound(c.round).Before(time.Unix(c.time, 0)))
+	}
+
+	// a resharing takes over at the first round due at its transition time
+	require.Equal(t, uint64(1), group.FirstRound())
+	h1, err := group.Hash()
+	require.NoError(t, err)
+	group.TransitionTime = 1031
+	require.Equal(t, uint64(3), group.FirstRound())
+	h2, err := group.Hash()
+	require.NoError(t, err)
+	require.NotEqual(t, h1, h2)
+	loaded := &Group{}
+	require.NoError(t, loaded.FromTOML(group.TOML()))
+	require.Equal(t, group.TransitionTime, loaded.TransitionTime)
+	// but the next resharing needs its own
+	require.Zero(t, group.MergeGroup(nil).TransitionTime)
+
+	// sub-second periods are supported
+	group.Period = 500 * time.Millisecond
+	require.Equal(t, uint64(3), group.RoundAt(time.Unix(1001, 0)))
+	require.Equal(t, time.Unix(1001, 0), group.TimeOfRound(3))
 }
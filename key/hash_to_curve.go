@@ -0,0 +1,397 @@
+package key
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	kyber "go.dedis.ch/kyber/v3"
+)
+
+// This file implements the hash to curve method of RFC 9380 for the groups of
+// the BN254 curve, also known as alt_bn128, which is the curve implemented by
+// kyber's bn256 package. Messages are expanded with expand_message_xmd using
+// SHA-256 and mapped to two field elements. Each element is mapped to the curve
+// with the Shallue-van de Woestijne (SvdW) method and the two resulting points
+// are added. This is the random oracle construction of the RFC.
+// The RFC defines no suite for BN254: the suite identifiers and domain
+// separation tags used here follow the convention of gnark-crypto, so the
+// hashes match its implementation and not a suite listed by the RFC.
+//  - for G1, it is the BN254G1_XMD:SHA-256_SVDW_RO_ suite of gnark-crypto. G1
+//  has a cofactor of one.
+//  - for G2, the same construction is applied on the twist of BN254 defined
+//  over Fp2, followed by a multiplication by the cofactor of G2.
+
+// bn254P is the characteristic of the base field of BN254.
+var bn254P, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+// bn254B is the constant of the curve equation y^2 = x^3 + 3.
+var bn254B = big.NewInt(3)
+
+// constants of the SvdW method for Z = 1, see section 6.6.1 of RFC 9380.
+var (
+	svdwZ     = big.NewInt(1)
+	svdwC1    = big.NewInt(4)
+	svdwC2, _ = new(big.Int).SetString("10944121435919637611123202872628637544348155578648911831344518947322613104291", 10)
+	svdwC3, _ = new(big.Int).SetString("8815841940592487685674414971303048083897117035520822607866", 10)
+	svdwC4, _ = new(big.Int).SetString("7296080957279758407415468581752425029565437052432607887563012631548408736189", 10)
+)
+
+// hashToFieldLen is the number of bytes expanded per element of Fp:
+// ceil((ceil(log2(p)) + k) / 8) with k = 128.
+const hashToFieldLen = 48
+
+// hashToG1 hashes the message to a point of G1 using the given domain
+// separation tag.
+func hashToG1(msg, dst []byte) (kyber.Point, error) {
+	uniform, err := expandMessageXMD(msg, dst, 2*hashToFieldLen)
+	if err != nil {
+		return nil, err
+	}
+	res := G1.Point().Null()
+	for i := 0; i < 2; i++ {
+		u := new(big.Int).SetBytes(uniform[i*hashToFieldLen : (i+1)*hashToFieldLen])
+		u.Mod(u, bn254P)
+		x, y := mapToCurveSvdW(u)
+		p, err := affineToG1(x, y)
+		if err != nil {
+			return nil, err
+		}
+		res.Add(res, p)
+	}
+	return res, nil
+}
+
+// expandMessageXMD implements expand_message_xmd of RFC 9380 with SHA-256.
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	ell := (length + sha256.Size - 1) / sha256.Size
+	if ell > 255 || length > 65535 || len(dst) > 255 {
+		return nil, errors.New("key: invalid expand_message_xmd parameters")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	out = append(out, bi...)
+	for i := 2; i <= ell; i++ {
+		xored := make([]byte, sha256.Size)
+		for j := range xored {
+			xored[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(xored)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// mapToCurveSvdW maps a field element to a point of the curve following the
+// straight-line implementation of the SvdW method given in appendix F.1 of
+// RFC 9380. It is not constant time, which is fine since it only hashes
+// public messages.
+func mapToCurveSvdW(u *big.Int) (*big.Int, *big.Int) {
+	p := bn254P
+	tv1 := new(big.Int).Mul(u, u)
+	tv1.Mul(tv1, svdwC1).Mod(tv1, p)
+	tv2 := new(big.Int).Add(big.NewInt(1), tv1)
+	tv2.Mod(tv2, p)
+	tv1.Sub(big.NewInt(1), tv1).Mod(tv1, p)
+	tv3 := new(big.Int).Mul(tv1, tv2)
+	tv3.Mod(tv3, p)
+	if tv3.Sign() != 0 {
+		tv3.ModInverse(tv3, p)
+	}
+	tv4 := new(big.Int).Mul(u, tv1)
+	tv4.Mul(tv4, tv3).Mul(tv4, svdwC3).Mod(tv4, p)
+
+	x1 := new(big.Int).Sub(svdwC2, tv4)
+	x1.Mod(x1, p)
+	x2 := new(big.Int).Add(svdwC2, tv4)
+	x2.Mod(x2, p)
+	x3 := new(big.Int).Mul(tv2, tv2)
+	x3.Mul(x3, tv3).Mod(x3, p)
+	x3.Mul(x3, x3).Mul(x3, svdwC4).Add(x3, svdwZ).Mod(x3, p)
+
+	var x *big.Int
+	switch {
+	case isSquare(curveRHS(x1)):
+		x = x1
+	case isSquare(curveRHS(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y := new(big.Int).ModSqrt(curveRHS(x), p)
+	if u.Bit(0) != y.Bit(0) {
+		y.Sub(p, y).Mod(y, p)
+	}
+	return x, y
+}
+
+// curveRHS returns x^3 + 3 mod p.
+func curveRHS(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Mul(r, x).Add(r, bn254B)
+	return r.Mod(r, bn254P)
+}
+
+func isSquare(x *big.Int) bool {
+	return big.Jacobi(x, bn254P) >= 0
+}
+
+// affineToG1 returns the G1 point with the given affine coordinates, using
+// the uncompressed x || y encoding of kyber's bn256 package.
+func affineToG1(x, y *big.Int) (kyber.Point, error) {
+	buff := make([]byte, 64)
+	xb, yb := x.Bytes(), y.Bytes()
+	copy(buff[32-len(xb):32], xb)
+	copy(buff[64-len(yb):], yb)
+	p := G1.Point()
+	if err := p.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fp2 is an element a + b*i of the quadratic extension Fp[i]/(i^2 + 1) over
+// which the twist of BN254 hosting G2 is defined. Operations return new
+// elements.
+type fp2 struct {
+	a, b *big.Int
+}
+
+func newFp2(a, b *big.Int) *fp2 {
+	return &fp2{new(big.Int).Mod(a, bn254P), new(big.Int).Mod(b, bn254P)}
+}
+
+func fp2FromStrings(a, b string) *fp2 {
+	x, _ := new(big.Int).SetString(a, 10)
+	y, _ := new(big.Int).SetString(b, 10)
+	return newFp2(x, y)
+}
+
+func (x *fp2) add(y *fp2) *fp2 {
+	return newFp2(new(big.Int).Add(x.a, y.a), new(big.Int).Add(x.b, y.b))
+}
+
+func (x *fp2) sub(y *fp2) *fp2 {
+	return newFp2(new(big.Int).Sub(x.a, y.a), new(big.Int).Sub(x.b, y.b))
+}
+
+func (x *fp2) neg() *fp2 {
+	return newFp2(new(big.Int).Neg(x.a), new(big.Int).Neg(x.b))
+}
+
+func (x *fp2) mul(y *fp2) *fp2 {
+	a := new(big.Int).Mul(x.a, y.a)
+	a.Sub(a, new(big.Int).Mul(x.b, y.b))
+	b := new(big.Int).Mul(x.a, y.b)
+	b.Add(b, new(big.Int).Mul(x.b, y.a))
+	return newFp2(a, b)
+}
+
+// norm returns a^2 + b^2, the norm of x over Fp.
+func (x *fp2) norm() *big.Int {
+	n := new(big.Int).Mul(x.a, x.a)
+	n.Add(n, new(big.Int).Mul(x.b, x.b))
+	return n.Mod(n, bn254P)
+}
+
+// inv returns the inverse of x, or zero if x is zero.
+func (x *fp2) inv() *fp2 {
+	n := x.norm()
+	if n.Sign() == 0 {
+		return newFp2(new(big.Int), new(big.Int))
+	}
+	n.ModInverse(n, bn254P)
+	return newFp2(new(big.Int).Mul(x.a, n), new(big.Int).Neg(new(big.Int).Mul(x.b, n)))
+}
+
+func (x *fp2) isZero() bool {
+	return x.a.Sign() == 0 && x.b.Sign() == 0
+}
+
+func (x *fp2) equal(y *fp2) bool {
+	return x.a.Cmp(y.a) == 0 && x.b.Cmp(y.b) == 0
+}
+
+// isSquare uses the fact that x is a square in Fp2 if and only if its norm is
+// a square in Fp.
+func (x *fp2) isSquare() bool {
+	return isSquare(x.norm())
+}
+
+// sqrt returns a square root of x, which must be a square.
+func (x *fp2) sqrt() *fp2 {
+	p := bn254P
+	zero := new(big.Int)
+	if x.b.Sign() == 0 {
+		if isSquare(x.a) {
+			return newFp2(new(big.Int).ModSqrt(x.a, p), zero)
+		}
+		return newFp2(zero, new(big.Int).ModSqrt(new(big.Int).Sub(p, x.a), p))
+	}
+	alpha := new(big.Int).ModSqrt(x.norm(), p)
+	half := new(big.Int).ModInverse(big.NewInt(2), p)
+	delta := new(big.Int).Add(x.a, alpha)
+	delta.Mul(delta, half).Mod(delta, p)
+	if !isSquare(delta) {
+		delta.Sub(x.a, alpha).Mul(delta, half).Mod(delta, p)
+	}
+	a := new(big.Int).ModSqrt(delta, p)
+	b := new(big.Int).Lsh(a, 1)
+	b.ModInverse(b, p).Mul(b, x.b)
+	return newFp2(a, b)
+}
+
+// sgn0 implements the sign function of RFC 9380 for an extension of degree 2.
+func (x *fp2) sgn0() uint {
+	return x.a.Bit(0) | (boolToUint(x.a.Sign() == 0) & x.b.Bit(0))
+}
+
+func boolToUint(b bool) uint {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// bn254G2Cofactor is the cofactor of G2 in the twist, 2p - r.
+var bn254G2Cofactor, _ = new(big.Int).SetString("21888242871839275222246405745257275088844257914179612981679871602714643921549", 10)
+
+// twistB is the constant of the twist equation y^2 = x^3 + 3 / (9 + i).
+var twistB = fp2FromStrings("19485874751759354771024239261021720505790618469301721065564631296452457478373", "266929791119991161246907387137283842545076965332900288569378510910307636690")
+
+// constants of the SvdW method on the twist for Z = 1.
+var (
+	twistSvdwZ  = fp2FromStrings("1", "0")
+	twistSvdwC1 = fp2FromStrings("19485874751759354771024239261021720505790618469301721065564631296452457478374", "266929791119991161246907387137283842545076965332900288569378510910307636690")
+	twistSvdwC2 = fp2FromStrings("10944121435919637611123202872628637544348155578648911831344518947322613104291", "0")
+	twistSvdwC3 = fp2FromStrings("18992192239972082890849143911285057164064277369389217330423471574879236301292", "21819008332247140148575583693947636719449476128975323941588917397607662637108")
+	twistSvdwC4 = fp2FromStrings("10499238450719652342378357227399831140106360636427411350395554762472100376473", "6940174569119770192419592065569379906172001098655407502803841283667998553941")
+)
+
+// hashToG2 hashes the message to a point of G2 using the given domain
+// separation tag.
+func hashToG2(msg, dst []byte) (kyber.Point, error) {
+	uniform, err := expandMessageXMD(msg, dst, 4*hashToFieldLen)
+	if err != nil {
+		return nil, err
+	}
+	var e [4]*big.Int
+	for i := range e {
+		e[i] = new(big.Int).SetBytes(uniform[i*hashToFieldLen : (i+1)*hashToFieldLen])
+	}
+	q0 := mapToTwistSvdW(newFp2(e[0], e[1]))
+	q1 := mapToTwistSvdW(newFp2(e[2], e[3]))
+	r := q0.add(q1).mul(bn254G2Cofactor)
+	return affineToG2(r)
+}
+
+// mapToTwistSvdW is the SvdW method of mapToCurveSvdW applied on the twist.
+func mapToTwistSvdW(u *fp2) *twistPoint {
+	one := fp2FromStrings("1", "0")
+	tv1 := u.mul(u).mul(twistSvdwC1)
+	tv2 := one.add(tv1)
+	tv1 = one.sub(tv1)
+	tv3 := tv1.mul(tv2).inv()
+	tv4 := u.mul(tv1).mul(tv3).mul(twistSvdwC3)
+	x1 := twistSvdwC2.sub(tv4)
+	x2 := twistSvdwC2.add(tv4)
+	x3 := tv2.mul(tv2).mul(tv3)
+	x3 = x3.mul(x3).mul(twistSvdwC4).add(twistSvdwZ)
+
+	var x *fp2
+	switch {
+	case twistRHS(x1).isSquare():
+		x = x1
+	case twistRHS(x2).isSquare():
+		x = x2
+	default:
+		x = x3
+	}
+	y := twistRHS(x).sqrt()
+	if u.sgn0() != y.sgn0() {
+		y = y.neg()
+	}
+	return &twistPoint{x: x, y: y}
+}
+
+// twistRHS returns x^3 + 3 / (9 + i).
+func twistRHS(x *fp2) *fp2 {
+	return x.mul(x).mul(x).add(twistB)
+}
+
+// twistPoint is a point of the twist in affine coordinates. The point at
+// infinity is represented by a nil x coordinate. kyber only accepts points of
+// G2, so the cofactor must be cleared with this representation.
+type twistPoint struct {
+	x, y *fp2
+}
+
+func (p *twistPoint) add(q *twistPoint) *twistPoint {
+	if p.x == nil {
+		return q
+	}
+	if q.x == nil {
+		return p
+	}
+	var l *fp2
+	if p.x.equal(q.x) {
+		if p.y.add(q.y).isZero() {
+			return &twistPoint{}
+		}
+		three := fp2FromStrings("3", "0")
+		l = three.mul(p.x).mul(p.x).mul(p.y.add(p.y).inv())
+	} else {
+		l = q.y.sub(p.y).mul(q.x.sub(p.x).inv())
+	}
+	x := l.mul(l).sub(p.x).sub(q.x)
+	y := l.mul(p.x.sub(x)).sub(p.y)
+	return &twistPoint{x: x, y: y}
+}
+
+func (p *twistPoint) mul(k *big.Int) *twistPoint {
+	r := &twistPoint{}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		r = r.add(r)
+		if k.Bit(i) == 1 {
+			r = r.add(p)
+		}
+	}
+	return r
+}
+
+// affineToG2 returns the G2 point corresponding to p, using the uncompressed
+// encoding of kyber's bn256 package where the imaginary part of each
+// coordinate comes first.
+func affineToG2(p *twistPoint) (kyber.Point, error) {
+	point := G2.Point()
+	if p.x == nil {
+		return point.Null(), nil
+	}
+	buff := make([]byte, 128)
+	for i, c := range []*big.Int{p.x.b, p.x.a, p.y.b, p.y.a} {
+		cb := c.Bytes()
+		copy(buff[(i+1)*32-len(cb):(i+1)*32], cb)
+	}
+	if err := point.UnmarshalBinary(buff); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
@@ -3,6 +3,7 @@ package key
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -30,6 +31,9 @@ var G2 = Pairing.G2()
 type Pair struct {
 	Key    kyber.Scalar
 	Public *Identity
+	// signer performs the private operations when the private key is held by
+	// an external signer, Key being nil.
+	signer Signer
 }
 
 // Identity holds the corresponding public key of a Private. It also includes a
@@ -39,6 +43,19 @@ type Identity struct {
 	Key  kyber.Point
 	Addr string
 	TLS  bool
+	// Transport is the transport the node accepts the requests of other nodes
+	// over, as named in the net package. Empty means gRPC.
+	Transport string
+	// Optional metadata describing the node to the other operators.
+	Name    string
+	Contact string
+	Region  string
+	// TLSFingerprint is the hex-encoded SHA-256 hash of the TLS certificate
+	// of the node, empty if unknown.
+	TLSFingerprint string
+	// Signature is the self-signature of the identity by its long-term key,
+	// see SelfSign. It is nil for identities created before signatures.
+	Signature []byte
 }
 
 // Address implements the net.Peer interface
@@ -51,12 +68,24 @@ func (i *Identity) IsTLS() bool {
 	return i.TLS
 }
 
+// PeerTransport implements the net.TransportPeer interface.
+func (i *Identity) PeerTransport() string {
+	return i.Transport
+}
+
 // NewKeyPair returns a freshly created private / public key pair. The group is
 // decided by the group variable by default. Currently, drand only supports
 // bn256.
 func NewKeyPair(address string) *Pair {
-	key := G2.Scalar().Pick(random.New())
-	pubKey := G2.Point().Mul(key, nil)
+	return NewKeyPairIn(G2, address)
+}
+
+// NewKeyPairIn returns a freshly created private / public key pair with the
+// public key on the given group. It must be the key group of the scheme of the
+// group the node participates in.
+func NewKeyPairIn(g kyber.Group, address string) *Pair {
+	key := g.Scalar().Pick(random.New())
+	pubKey := g.Point().Mul(key, nil)
 	pub := &Identity{
 		Key:  pubKey,
 		Addr: address,
@@ -75,6 +104,63 @@ func NewTLSKeyPair(address string) *Pair {
 	return kp
 }
 
+// identityDomain separates the self-signatures of identities from the other
+// messages signed by the long-term key.
+const identityDomain = "drand-identity-v1"
+
+// signedMessage returns the message self-signed by the identity: all its
+// fields but the signature, each prefixed by its length.
+func (i *Identity) signedMessage() ([]byte, error) {
+	key, err := i.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	tls := "false"
+	if i.TLS {
+		tls = "true"
+	}
+	var buff bytes.Buffer
+	buff.WriteString(identityDomain)
+	for _, f := range []string{string(key), i.Addr, tls, i.Transport, i.TLSFingerprint, i.Name, i.Contact, i.Region} {
+		binary.Write(&buff, binary.BigEndian, uint32(len(f)))
+		buff.WriteString(f)
+	}
+	return buff.Bytes(), nil
+}
+
+// SelfSign signs the public identity of the pair with its long-term key, so
+// the other nodes can detect if its address, TLS settings or metadata are
+// modified, for example in a group file. It must be called again after any
+// change to the identity.
+func (p *Pair) SelfSign() error {
+	msg, err := p.Public.signedMessage()
+	if err != nil {
+		return err
+	}
+	p.Public.Signature, err = p.AuthSign(msg)
+	return err
+}
+
+// IsSigned returns true if the identity carries a self-signature.
+func (i *Identity) IsSigned() bool {
+	return len(i.Signature) > 0
+}
+
+// VerifySignature checks the self-signature of the identity.
+func (i *Identity) VerifySignature() error {
+	if !i.IsSigned() {
+		return errors.New("identity: not signed")
+	}
+	msg, err := i.signedMessage()
+	if err != nil {
+		return err
+	}
+	if err := i.AuthVerify(msg, i.Signature); err != nil {
+		return fmt.Errorf("identity %s: invalid self-signature: %s", i.Addr, err)
+	}
+	return nil
+}
+
 // PairTOML is the TOML-able version of a private key
 type PairTOML struct {
 	Key string
@@ -82,9 +168,15 @@ type PairTOML struct {
 
 // PublicTOML is the TOML-able version of a public key
 type PublicTOML struct {
-	Address string
-	Key     string
-	TLS     bool
+	Address        string
+	Key            string
+	TLS            bool
+	Transport      string `toml:",omitempty"`
+	Name           string `toml:",omitempty"`
+	Contact        string `toml:",omitempty"`
+	Region         string `toml:",omitempty"`
+	TLSFingerprint string `toml:",omitempty"`
+	Signature      string `toml:",omitempty"`
 }
 
 // TOML returns a struct that can be marshalled using a TOML-encoding library
@@ -122,29 +214,47 @@ func (i *Identity) Equal(p2 *Identity) bool {
 	return i.Key.Equal(p2.Key)
 }
 
-// FromTOML loads reads the TOML description of the public key
+// FromTOML loads reads the TOML description of the public key. A signed
+// identity must carry a valid self-signature, so tampered identities are
+// rejected.
 func (i *Identity) FromTOML(t interface{}) error {
 	ptoml, ok := t.(*PublicTOML)
 	if !ok {
 		return errors.New("Public can't decode from non PublicTOML struct")
 	}
-	buff, err := hex.DecodeString(ptoml.Key)
-	if err != nil {
-		return err
-	}
 	i.Addr = ptoml.Address
-	i.Key = G2.Point()
 	i.TLS = ptoml.TLS
-	return i.Key.UnmarshalBinary(buff)
+	i.Transport = ptoml.Transport
+	i.Name = ptoml.Name
+	i.Contact = ptoml.Contact
+	i.Region = ptoml.Region
+	i.TLSFingerprint = ptoml.TLSFingerprint
+	var err error
+	if i.Key, err = StringToKeyPoint(ptoml.Key); err != nil {
+		return err
+	}
+	i.Signature = nil
+	if ptoml.Signature == "" {
+		return nil
+	}
+	if i.Signature, err = hex.DecodeString(ptoml.Signature); err != nil {
+		return fmt.Errorf("identity %s: invalid signature encoding: %s", i.Addr, err)
+	}
+	return i.VerifySignature()
 }
 
 // TOML returns a empty TOML-compatible version of the public key
 func (i *Identity) TOML() interface{} {
-	hex := PointToString(i.Key)
 	return &PublicTOML{
-		Address: i.Addr,
-		Key:     hex,
-		TLS:     i.TLS,
+		Address:        i.Addr,
+		Key:            PointToString(i.Key),
+		TLS:            i.TLS,
+		Transport:      i.Transport,
+		Name:           i.Name,
+		Contact:        i.Contact,
+		Region:         i.Region,
+		TLSFingerprint: i.TLSFingerprint,
+		Signature:      hex.EncodeToString(i.Signature),
 	}
 }
 
@@ -204,7 +314,7 @@ func (s *Share) FromTOML(i interface{}) error {
 	}
 	s.Commits = make([]kyber.Point, len(t.Commits))
 	for i, c := range t.Commits {
-		p, err := StringToPoint(G2, c)
+		p, err := StringToKeyPoint(c)
 		if err != nil {
 			return fmt.Errorf("share.Commit[%d] corruputed: %s", i, err)
 		}
@@ -282,7 +392,7 @@ func (d *DistPublic) FromTOML(i interface{}) error {
 	points := make([]kyber.Point, len(dtoml.Coefficients))
 	var err error
 	for i, s := range dtoml.Coefficients {
-		points[i], err = StringToPoint(G2, s)
+		points[i], err = StringToKeyPoint(s)
 		if err != nil {
 			return err
 		}
@@ -386,6 +496,30 @@ func StringToPoint(g kyber.Group, s string) (kyber.Point, error) {
 	return p, p.UnmarshalBinary(buff)
 }
 
+// StringToKeyPoint unmarshals a key from the given string. Keys can lie on G1
+// or G2, depending on the scheme of the group, and the points of both groups
+// have different encoding lengths, so the group is determined by the length of
+// the encoding.
+func StringToKeyPoint(s string) (kyber.Point, error) {
+	buff, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range []kyber.Group{G2, G1} {
+		if len(buff) == g.PointLen() {
+			p := g.Point()
+			return p, p.UnmarshalBinary(buff)
+		}
+	}
+	return nil, fmt.Errorf("key: invalid key length %d", len(buff))
+}
+
+// InGroup returns true if the point belongs to the given group.
+func InGroup(p kyber.Point, g kyber.Group) bool {
+	groupable, ok := p.(kyber.Groupable)
+	return ok && groupable.Group().String() == g.String()
+}
+
 // StringToScalar unmarshals a scalar in the given group from the given string.
 func StringToScalar(g kyber.Group, s string) (kyber.Scalar, error) {
 	buff, err := hex.DecodeString(s)
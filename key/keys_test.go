@@ -117,3 +117,53 @@ func BatchIdentities(n int) ([]*Pair, *Group) {
 	}
 	return privs, group
 }
+
+func TestKeyAuthSign(t *testing.T) {
+	msg := []byte("hello world")
+	for _, g := range []kyber.Group{G2, G1} {
+		pair := NewKeyPairIn(g, "127.0.0.1:80")
+		sig, err := pair.AuthSign(msg)
+		require.NoError(t, err)
+		require.NoError(t, pair.Public.AuthVerify(msg, sig))
+		require.Error(t, pair.Public.AuthVerify([]byte("another message"), sig))
+
+		other := NewKeyPairIn(g, "127.0.0.1:81")
+		require.Error(t, other.Public.AuthVerify(msg, sig))
+	}
+}
+
+func TestKeySelfSign(t *testing.T) {
+	pair := NewTLSKeyPair("127.0.0.1:80")
+	pair.Public.Name = "node"
+	pair.Public.Contact = "ops@example.com"
+	pair.Public.Region = "eu"
+	pair.Public.TLSFingerprint = "aabbcc"
+	require.Error(t, pair.Public.VerifySignature())
+	require.NoError(t, pair.SelfSign())
+	require.NoError(t, pair.Public.VerifySignature())
+
+	ptoml := pair.Public.TOML().(*PublicTOML)
+	loaded := new(Identity)
+	require.NoError(t, loaded.FromTOML(ptoml))
+	require.Equal(t, pair.Public.Name, loaded.Name)
+	require.Equal(t, pair.Public.Contact, loaded.Contact)
+	require.Equal(t, pair.Public.Region, loaded.Region)
+	require.Equal(t, pair.Public.TLSFingerprint, loaded.TLSFingerprint)
+	require.True(t, loaded.IsSigned())
+
+	// any modification of the signed fields is detected
+	tampered := *ptoml
+	tampered.Address = "127.0.0.1:81"
+	require.Error(t, new(Identity).FromTOML(&tampered))
+	tampered = *ptoml
+	tampered.TLS = false
+	require.Error(t, new(Identity).FromTOML(&tampered))
+	tampered = *ptoml
+	tampered.Region = "us"
+	require.Error(t, new(Identity).FromTOML(&tampered))
+
+	// unsigned identities are still accepted
+	tampered = *ptoml
+	tampered.Signature = ""
+	require.NoError(t, new(Identity).FromTOML(&tampered))
+}
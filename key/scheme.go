@@ -0,0 +1,246 @@
+package key
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// Scheme holds the cryptographic choices used by a beacon network to produce
+// and verify its randomness: the pairing suite, the groups of the keys and of
+// the signatures and the way messages are hashed to the signature group.
+// Signatures are BLS signatures. Partial signatures are prefixed by the index
+// of the signer encoded as a big endian uint16, as in kyber's tbls package.
+type Scheme struct {
+	// Name identifies the scheme in the group file.
+	Name string
+	// Suite is the pairing suite the keys and signatures belong to.
+	Suite pairing.Suite
+	// KeyGroup is the group of the long-term and distributed keys.
+	KeyGroup kyber.Group
+	// SigGroup is the group of the signatures, the other group of the pairing.
+	SigGroup kyber.Group
+	// hash maps a message to the signature group.
+	hash func(msg []byte) (kyber.Point, error)
+}
+
+const (
+	// SchemeKyberBN256 is the original scheme of drand, compatible with
+	// kyber's bls package. It uses the hash to point method of kyber's bn256
+	// package which is specific to kyber.
+	SchemeKyberBN256 = "bls-bn256-kyber"
+	// SchemeRFC9380BN254 hashes messages to the signature group with the
+	// SvdW method of RFC 9380. The RFC defines no suite for BN254: on G1, it
+	// follows the BN254G1_XMD:SHA-256_SVDW_RO_ suite of gnark-crypto, which
+	// makes signatures verifiable by the implementations using it on BN254,
+	// also known as alt_bn128 and available as precompiles on Ethereum.
+	SchemeRFC9380BN254 = "bls-bn254-rfc9380"
+)
+
+// RFC9380DST is the domain separation tag used to hash messages to G1 with the
+// SchemeRFC9380BN254 scheme.
+const RFC9380DST = "BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_NUL_"
+
+// RFC9380DSTG2 is the domain separation tag used to hash messages to G2 with
+// the SchemeRFC9380BN254 scheme.
+const RFC9380DSTG2 = "BLS_SIG_BN254G2_XMD:SHA-256_SVDW_RO_NUL_"
+
+const (
+	// KeyGroupG2 puts the keys on G2 and the signatures on G1, giving short
+	// signatures. It is the default.
+	KeyGroupG2 = "G2"
+	// KeyGroupG1 puts the keys on G1 and the signatures on G2, giving short
+	// public keys.
+	KeyGroupG1 = "G1"
+)
+
+// DefaultScheme is the scheme used by groups that do not specify one.
+var DefaultScheme = SchemeFromNameOrPanic(SchemeKyberBN256, KeyGroupG2)
+
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// SchemeFromName returns the scheme registered under the given name with its
+// keys on the given group, KeyGroupG2 or KeyGroupG1. Empty names return the
+// default values.
+func SchemeFromName(name, keyGroup string) (*Scheme, error) {
+	s := &Scheme{Suite: Pairing}
+	kg, err := KeyGroupFromName(keyGroup)
+	if err != nil {
+		return nil, err
+	}
+	if kg == G2 {
+		s.KeyGroup, s.SigGroup = G2, G1
+	} else {
+		s.KeyGroup, s.SigGroup = G1, G2
+	}
+	switch name {
+	case "", SchemeKyberBN256:
+		s.Name = SchemeKyberBN256
+		if s.KeyGroup != G2 {
+			return nil, fmt.Errorf("key: scheme %s only supports keys on %s", s.Name, KeyGroupG2)
+		}
+		s.hash = func(msg []byte) (kyber.Point, error) {
+			return G1.Point().(hashablePoint).Hash(msg), nil
+		}
+	case SchemeRFC9380BN254:
+		s.Name = SchemeRFC9380BN254
+		if s.SigGroup == G1 {
+			s.hash = func(msg []byte) (kyber.Point, error) {
+				return hashToG1(msg, []byte(RFC9380DST))
+			}
+		} else {
+			s.hash = func(msg []byte) (kyber.Point, error) {
+				return hashToG2(msg, []byte(RFC9380DSTG2))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("key: unknown scheme %q", name)
+	}
+	return s, nil
+}
+
+// SchemeFromNameOrPanic is like SchemeFromName but panics if the scheme is
+// unknown.
+func SchemeFromNameOrPanic(name, keyGroup string) *Scheme {
+	s, err := SchemeFromName(name, keyGroup)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// KeyGroupFromName returns the group designated by KeyGroupG2 or KeyGroupG1.
+// An empty name returns the default, G2.
+func KeyGroupFromName(name string) (kyber.Group, error) {
+	switch name {
+	case "", KeyGroupG2:
+		return G2, nil
+	case KeyGroupG1:
+		return G1, nil
+	}
+	return nil, fmt.Errorf("key: unknown key group %q", name)
+}
+
+// KeyGroupName returns the name of the group of the keys, KeyGroupG2 or
+// KeyGroupG1.
+func (s *Scheme) KeyGroupName() string {
+	if s.KeyGroup.String() == G1.String() {
+		return KeyGroupG1
+	}
+	return KeyGroupG2
+}
+
+// Equal returns true if both schemes produce the same signatures.
+func (s *Scheme) Equal(s2 *Scheme) bool {
+	return s.Name == s2.Name && s.KeyGroup.String() == s2.KeyGroup.String()
+}
+
+// HashToPoint returns the point of the signature group the message is mapped
+// to.
+func (s *Scheme) HashToPoint(msg []byte) (kyber.Point, error) {
+	return s.hash(msg)
+}
+
+// Sign returns the BLS signature of the message under the given private key.
+func (s *Scheme) Sign(private kyber.Scalar, msg []byte) ([]byte, error) {
+	hm, err := s.hash(msg)
+	if err != nil {
+		return nil, err
+	}
+	return hm.Mul(private, hm).MarshalBinary()
+}
+
+// Verify checks the BLS signature of the message under the given public key.
+func (s *Scheme) Verify(public kyber.Point, msg, sig []byte) error {
+	hm, err := s.hash(msg)
+	if err != nil {
+		return err
+	}
+	sigPoint := s.SigGroup.Point()
+	if err := sigPoint.UnmarshalBinary(sig); err != nil {
+		return err
+	}
+	var left, right kyber.Point
+	if s.SigGroup.String() == G1.String() {
+		left = s.Suite.Pair(hm, public)
+		right = s.Suite.Pair(sigPoint, G2.Point().Base())
+	} else {
+		left = s.Suite.Pair(public, hm)
+		right = s.Suite.Pair(G1.Point().Base(), sigPoint)
+	}
+	if !left.Equal(right) {
+		return errors.New("key: invalid signature")
+	}
+	return nil
+}
+
+// PartialSign returns the partial signature of the message under the given
+// private share.
+func (s *Scheme) PartialSign(private *share.PriShare, msg []byte) ([]byte, error) {
+	sig, err := s.Sign(private.V, msg)
+	if err != nil {
+		return nil, err
+	}
+	var buff bytes.Buffer
+	binary.Write(&buff, binary.BigEndian, uint16(private.I))
+	buff.Write(sig)
+	return buff.Bytes(), nil
+}
+
+// PublicPoly returns the public polynomial of a distributed key from its
+// commitments.
+func (s *Scheme) PublicPoly(commits []kyber.Point) *share.PubPoly {
+	return share.NewPubPoly(s.KeyGroup, s.KeyGroup.Point().Base(), commits)
+}
+
+// PartialVerify checks the partial signature of the message against the
+// public polynomial of the group.
+func (s *Scheme) PartialVerify(public *share.PubPoly, msg, sig []byte) error {
+	i, value, err := splitPartial(sig)
+	if err != nil {
+		return err
+	}
+	return s.Verify(public.Eval(i).V, msg, value)
+}
+
+// Recover verifies the given partial signatures and reconstructs the full
+// signature of the message from a threshold t of them, out of n signers.
+func (s *Scheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	var pubShares []*share.PubShare
+	for _, sig := range sigs {
+		i, value, err := splitPartial(sig)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Verify(public.Eval(i).V, msg, value); err != nil {
+			return nil, err
+		}
+		point := s.SigGroup.Point()
+		if err := point.UnmarshalBinary(value); err != nil {
+			return nil, err
+		}
+		pubShares = append(pubShares, &share.PubShare{I: i, V: point})
+		if len(pubShares) >= t {
+			break
+		}
+	}
+	commit, err := share.RecoverCommit(s.SigGroup, pubShares, t, n)
+	if err != nil {
+		return nil, err
+	}
+	return commit.MarshalBinary()
+}
+
+func splitPartial(sig []byte) (int, []byte, error) {
+	if len(sig) < 2 {
+		return 0, nil, errors.New("key: partial signature too short")
+	}
+	return int(binary.BigEndian.Uint16(sig[:2])), sig[2:], nil
+}
@@ -0,0 +1,151 @@
+package key
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kyber "go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/tbls"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestHashToG1Vectors(t *testing.T) {
+	// test vectors of the BN254G1_XMD:SHA-256_SVDW_RO_ suite
+	dst := []byte("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+	vectors := []struct {
+		msg string
+		x   string
+		y   string
+	}{
+		{
+			"",
+			"0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86",
+			"02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5",
+		},
+		{
+			"abc",
+			"23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1",
+			"04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d",
+		},
+		{
+			"abcdef0123456789",
+			"187dbf1c3c89aceceef254d6548d7163fdfa43084145f92c4c91c85c21442d4a",
+			"0abd99d5b0000910b56058f9cc3b0ab0a22d47cf27615f588924fac1e5c63b4d",
+		},
+	}
+	for _, v := range vectors {
+		p, err := hashToG1([]byte(v.msg), dst)
+		require.NoError(t, err)
+		buff, err := p.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, v.x+v.y, hex.EncodeToString(buff), "msg %q", v.msg)
+	}
+}
+
+func TestSchemeKyberCompatible(t *testing.T) {
+	scheme, err := SchemeFromName("", "")
+	require.NoError(t, err)
+	require.Equal(t, SchemeKyberBN256, scheme.Name)
+	require.Equal(t, KeyGroupG2, scheme.KeyGroupName())
+	require.True(t, scheme.Equal(DefaultScheme))
+
+	n, thr := 5, 3
+	shares, pub := schemeShares(G2, n, thr)
+	msg := []byte("Hello drand")
+
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		sigs[i], err = tbls.Sign(Pairing, shares[i], msg)
+		require.NoError(t, err)
+		require.NoError(t, scheme.PartialVerify(pub, msg, sigs[i]))
+		partial, err := scheme.PartialSign(shares[i], msg)
+		require.NoError(t, err)
+		require.Equal(t, sigs[i], partial)
+	}
+	sig, err := scheme.Recover(pub, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(Pairing, pub.Commit(), msg, sig))
+	require.NoError(t, scheme.Verify(pub.Commit(), msg, sig))
+}
+
+func TestSchemeRFC9380(t *testing.T) {
+	scheme, err := SchemeFromName(SchemeRFC9380BN254, KeyGroupG2)
+	require.NoError(t, err)
+
+	n, thr := 5, 3
+	shares, pub := schemeShares(G2, n, thr)
+	msg := []byte("Hello drand")
+
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		sigs[i], err = scheme.PartialSign(shares[i], msg)
+		require.NoError(t, err)
+		require.NoError(t, scheme.PartialVerify(pub, msg, sigs[i]))
+	}
+	require.Error(t, scheme.PartialVerify(pub, []byte("other"), sigs[0]))
+	sig, err := scheme.Recover(pub, msg, sigs[1:], thr, n)
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(pub.Commit(), msg, sig))
+	require.Error(t, scheme.Verify(pub.Commit(), []byte("other"), sig))
+	// signatures are not valid under kyber's hash to point
+	require.Error(t, bls.Verify(Pairing, pub.Commit(), msg, sig))
+
+	_, err = SchemeFromName("unknown", "")
+	require.Error(t, err)
+}
+
+func TestHashToG2(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BN254G2_XMD:SHA-256_SVDW_RO_")
+	vectors := map[string]string{
+		"": "303dbd430a583c946596158ce500a5ff37babc6dd1ed482aca4daa881581480c" +
+			"22cef87c4dd45a4cc4d32df4295ba3c3e488bd331b07b6b2514b25cf5aeb7cf3" +
+			"2eca508abbba76b69a78f7b8c2d22b03403b216a091195a834b64bc512099e12" +
+			"27054759d88e6b9a0b3419858be3b27c3a3d53d21f744e73356a5c41b9a4e815",
+		"abc": "0ab016609756d6c217d6c0e41ba9b9202b82ef8f1bb86ec51bc4c02a3c8acbbf" +
+			"28f105b439abd57dfdd29c4818df5e8ed9b0f67296e5cdd178864ca6e75c36ce" +
+			"0efbfebf454feaa177a5c97b70b665e7b239f5b63599bc225848321ed059e010" +
+			"18bafc8d9cae1eff18aebc4da5803046da89ff3e30c5214618ec396878299d43",
+	}
+	for msg, exp := range vectors {
+		p, err := hashToG2([]byte(msg), dst)
+		require.NoError(t, err)
+		buff, err := p.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, exp, hex.EncodeToString(buff), "msg %q", msg)
+	}
+}
+
+func TestSchemeKeysOnG1(t *testing.T) {
+	_, err := SchemeFromName(SchemeKyberBN256, KeyGroupG1)
+	require.Error(t, err)
+	scheme, err := SchemeFromName(SchemeRFC9380BN254, KeyGroupG1)
+	require.NoError(t, err)
+	require.Equal(t, KeyGroupG1, scheme.KeyGroupName())
+	require.False(t, scheme.Equal(SchemeFromNameOrPanic(SchemeRFC9380BN254, KeyGroupG2)))
+
+	n, thr := 5, 3
+	shares, pub := schemeShares(G1, n, thr)
+	msg := []byte("Hello drand")
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		sigs[i], err = scheme.PartialSign(shares[i], msg)
+		require.NoError(t, err)
+		require.NoError(t, scheme.PartialVerify(pub, msg, sigs[i]))
+	}
+	sig, err := scheme.Recover(pub, msg, sigs, thr, n)
+	require.NoError(t, err)
+	require.Len(t, sig, G2.PointLen())
+	require.NoError(t, scheme.Verify(pub.Commit(), msg, sig))
+	require.Error(t, scheme.Verify(pub.Commit(), []byte("other"), sig))
+
+	_, commits := pub.Info()
+	require.True(t, scheme.PublicPoly(commits).Equal(pub))
+}
+
+func schemeShares(g kyber.Group, n, t int) ([]*share.PriShare, *share.PubPoly) {
+	pri := share.NewPriPoly(g, t, g.Scalar().Pick(random.New()), random.New())
+	return pri.Shares(n), pri.Commit(g.Point().Base())
+}
@@ -0,0 +1,231 @@
+package key
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+
+	kyber "go.dedis.ch/kyber/v3"
+)
+
+// Signer performs the operations of a long-term private key. A Pair is a
+// Signer using its own key; NewExternalSigner returns a Signer delegating them
+// to another process, which can keep the key in hardware.
+type Signer interface {
+	// PublicKey returns the long-term public key.
+	PublicKey() kyber.Point
+	// DH returns the product of the private key with the given point, the
+	// Diffie-Hellman exchange used to decrypt the private randomness requests.
+	DH(p kyber.Point) (kyber.Point, error)
+	// AuthSign returns a Schnorr signature of the message, see Pair.AuthSign.
+	AuthSign(msg []byte) ([]byte, error)
+}
+
+// NewSignerPair returns a key pair at the given address whose private
+// operations are performed by the signer. The private key of the pair is nil,
+// so such a pair can not run a DKG nor be saved to a private key file.
+func NewSignerPair(s Signer, address string) *Pair {
+	return &Pair{
+		Public: &Identity{Key: s.PublicKey(), Addr: address},
+		signer: s,
+	}
+}
+
+// NewSignerFileStore returns a copy of the file store, as returned by
+// NewFileStore or NewEncryptedFileStore, whose key pair is held by the signer:
+// only the public identity is saved and loaded, and the private operations of
+// the loaded pair are performed by the signer.
+func NewSignerFileStore(s Store, signer Signer) (Store, error) {
+	f, ok := s.(*fileStore)
+	if !ok {
+		return nil, errors.New("store: external signers need a file store")
+	}
+	store := *f
+	store.signer = signer
+	return &store, nil
+}
+
+// PublicKey implements the Signer interface.
+func (p *Pair) PublicKey() kyber.Point {
+	return p.Public.Key
+}
+
+// DH implements the Signer interface.
+func (p *Pair) DH(pt kyber.Point) (kyber.Point, error) {
+	if p.signer != nil {
+		return p.signer.DH(pt)
+	}
+	g, err := keyGroup(p.Public.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !InGroup(pt, g) {
+		return nil, errors.New("key: point not on the group of the long-term key")
+	}
+	return g.Point().Mul(p.Key, pt), nil
+}
+
+// The external signer protocol is JSON-RPC 1.0, as implemented by the
+// net/rpc/jsonrpc package, over a local connection, usually a unix socket. A
+// signer process serves three methods, whose parameters and results are
+// SignerRequest and SignerResponse objects:
+//   - "Signer.PublicKey" returns the hex-encoded public key in Key
+//   - "Signer.DH" returns in Point the product of the private key with the
+//     hex-encoded Point of the request
+//   - "Signer.AuthSign" returns in Sig the signature of the Msg of the
+//     request, both base64-encoded, see Pair.AuthSign
+// ServeSigner implements the protocol for any Signer.
+
+// SignerRequest holds the parameters of the external signer protocol.
+type SignerRequest struct {
+	Point string `json:",omitempty"`
+	Msg   []byte `json:",omitempty"`
+}
+
+// SignerResponse holds the results of the external signer protocol.
+type SignerResponse struct {
+	Key   string `json:",omitempty"`
+	Point string `json:",omitempty"`
+	Sig   []byte `json:",omitempty"`
+}
+
+// signerService exposes a Signer through net/rpc.
+type signerService struct {
+	s Signer
+}
+
+func (s *signerService) PublicKey(req *SignerRequest, resp *SignerResponse) error {
+	resp.Key = PointToString(s.s.PublicKey())
+	return nil
+}
+
+func (s *signerService) DH(req *SignerRequest, resp *SignerResponse) error {
+	g, err := keyGroup(s.s.PublicKey())
+	if err != nil {
+		return err
+	}
+	pt, err := StringToPoint(g, req.Point)
+	if err != nil {
+		return err
+	}
+	dh, err := s.s.DH(pt)
+	if err != nil {
+		return err
+	}
+	resp.Point = PointToString(dh)
+	return nil
+}
+
+func (s *signerService) AuthSign(req *SignerRequest, resp *SignerResponse) error {
+	sig, err := s.s.AuthSign(req.Msg)
+	resp.Sig = sig
+	return err
+}
+
+// ServeSigner serves the signer over the external signer protocol on each
+// connection accepted by the listener, until it is closed. It is the signer
+// process for keys held in files, and a local stand-in for hardware signers
+// in tests.
+func ServeSigner(l net.Listener, s Signer) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Signer", &signerService{s}); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// externalSigner is a Signer delegating to a signer process.
+type externalSigner struct {
+	sync.Mutex
+	network string
+	addr    string
+	client  *rpc.Client
+	public  kyber.Point
+	group   kyber.Group
+}
+
+// NewExternalSigner returns a Signer delegating the operations to the signer
+// process listening at the given address, for example on the "unix" network.
+// The connection is dialed again if it breaks.
+func NewExternalSigner(network, addr string) (Signer, error) {
+	e := &externalSigner{network: network, addr: addr}
+	var resp SignerResponse
+	if err := e.call("Signer.PublicKey", &SignerRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	public, err := StringToKeyPoint(resp.Key)
+	if err != nil {
+		return nil, err
+	}
+	if e.group, err = keyGroup(public); err != nil {
+		return nil, err
+	}
+	e.public = public
+	return e, nil
+}
+
+// call calls the method of the signer process, dialing it if needed, and once
+// more on a fresh connection if the current one is broken. The operations of
+// the protocol have no side effect, so they can always be retried.
+func (e *externalSigner) call(method string, req *SignerRequest, resp *SignerResponse) error {
+	var err error
+	for retry := 0; retry < 2; retry++ {
+		var client *rpc.Client
+		if client, err = e.conn(); err != nil {
+			return err
+		}
+		err = client.Call(method, req, resp)
+		if _, ok := err.(rpc.ServerError); ok || err == nil {
+			return err
+		}
+		e.Lock()
+		if e.client == client {
+			client.Close()
+			e.client = nil
+		}
+		e.Unlock()
+	}
+	return err
+}
+
+func (e *externalSigner) conn() (*rpc.Client, error) {
+	e.Lock()
+	defer e.Unlock()
+	if e.client != nil {
+		return e.client, nil
+	}
+	conn, err := net.Dial(e.network, e.addr)
+	if err != nil {
+		return nil, err
+	}
+	e.client = jsonrpc.NewClient(conn)
+	return e.client, nil
+}
+
+func (e *externalSigner) PublicKey() kyber.Point {
+	return e.public
+}
+
+func (e *externalSigner) DH(pt kyber.Point) (kyber.Point, error) {
+	var resp SignerResponse
+	if err := e.call("Signer.DH", &SignerRequest{Point: PointToString(pt)}, &resp); err != nil {
+		return nil, err
+	}
+	return StringToPoint(e.group, resp.Point)
+}
+
+func (e *externalSigner) AuthSign(msg []byte) ([]byte, error) {
+	var resp SignerResponse
+	if err := e.call("Signer.AuthSign", &SignerRequest{Msg: msg}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Sig, nil
+}
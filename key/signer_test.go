@@ -0,0 +1,64 @@
+package key
+
+import (
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestExternalSigner(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-signer")
+	os.RemoveAll(tmp)
+	require.NoError(t, os.MkdirAll(tmp, 0700))
+	defer os.RemoveAll(tmp)
+	socket := path.Join(tmp, "signer.sock")
+
+	pair := NewKeyPair("127.0.0.1:8080")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	go ServeSigner(l, pair)
+
+	signer, err := NewExternalSigner("unix", socket)
+	require.NoError(t, err)
+	require.True(t, signer.PublicKey().Equal(pair.Public.Key))
+
+	point := G2.Point().Pick(random.New())
+	expected, err := pair.DH(point)
+	require.NoError(t, err)
+	dh, err := signer.DH(point)
+	require.NoError(t, err)
+	require.True(t, expected.Equal(dh))
+
+	msg := []byte("authenticated request")
+	sig, err := signer.AuthSign(msg)
+	require.NoError(t, err)
+	require.NoError(t, pair.Public.AuthVerify(msg, sig))
+
+	// the store keeps only the public identity of pairs held by a signer
+	store, err := NewSignerFileStore(NewFileStore(tmp), signer)
+	require.NoError(t, err)
+	external := NewSignerPair(signer, "127.0.0.1:8080")
+	require.Nil(t, external.Key)
+	require.NoError(t, store.SaveKeyPair(external))
+	_, err = os.Stat(store.(*fileStore).privateKeyFile)
+	require.True(t, os.IsNotExist(err))
+	loaded, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	sig, err = loaded.AuthSign(msg)
+	require.NoError(t, err)
+	require.NoError(t, pair.Public.AuthVerify(msg, sig))
+
+	other, err := NewSignerFileStore(NewFileStore(tmp), NewKeyPair("127.0.0.1:8081"))
+	require.NoError(t, err)
+	_, err = other.LoadKeyPair()
+	require.Error(t, err)
+
+	// a broken connection is dialed again
+	signer.(*externalSigner).client.Close()
+	_, err = signer.AuthSign(msg)
+	require.NoError(t, err)
+}
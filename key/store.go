@@ -13,7 +13,8 @@ import (
 
 // Store abstracts the loading and saving of any private/public cryptographic
 // material to be used by drand. For the moment, only a file based store is
-// implemented.
+// implemented, which can encrypt the private material, see
+// NewEncryptedFileStore.
 type Store interface {
 	// SaveKeyPair saves the private key generated by drand as well as the
 	// public identity key associated
@@ -27,6 +28,23 @@ type Store interface {
 	LoadGroup() (*Group, error)
 	SaveDistPublic(d *DistPublic) error
 	LoadDistPublic() (*DistPublic, error)
+	// SaveEpoch adds the epoch to the history of the groups and distributed
+	// keys of the node, with the share of the node if it is set.
+	SaveEpoch(e *Epoch) error
+	// LoadEpochs returns the history, ordered by epoch number, without the
+	// shares.
+	LoadEpochs() ([]*Epoch, error)
+	// LoadEpochShare returns the share of the node in the given epoch, if it
+	// was saved.
+	LoadEpochShare(n int) (*Share, error)
+	// SaveNextKeyPair saves the key pair replacing the current one at the
+	// next key rotation, alongside the current one.
+	SaveNextKeyPair(p *Pair) error
+	// LoadNextKeyPair loads the key pair saved by SaveNextKeyPair.
+	LoadNextKeyPair() (*Pair, error)
+	// RotateKeyPair replaces the current key pair with the next one, which
+	// is then removed.
+	RotateKeyPair() error
 }
 
 // ErrStoreFile returns an error in case the store can not save the requested
@@ -43,6 +61,7 @@ const ConfigFolderFlag = "homedir"
 const KeyFolderName = "key"
 const GroupFolderName = "groups"
 const keyFileName = "drand_id"
+const nextKeyFileName = "drand_id.next"
 const privateExtension = ".private"
 const publicExtension = ".public"
 const groupFileName = "drand_group.toml"
@@ -61,9 +80,16 @@ type fileStore struct {
 	baseFolder     string
 	privateKeyFile string
 	publicKeyFile  string
+	nextPrivateKey string
+	nextPublicKey  string
 	shareFile      string
 	distKeyFile    string
 	groupFile      string
+	historyFolder  string
+	// key encrypting the private files, nil for a plaintext store
+	key *StoreKey
+	// signer holding the private key, nil if it is in the private key file
+	signer Signer
 }
 
 // NewDefaultFileStore is used to create the config folder and all the subfolders.
@@ -78,31 +104,89 @@ func NewFileStore(baseFolder string) Store {
 	groupFolder := fs.CreateSecureFolder(path.Join(baseFolder, GroupFolderName))
 	store.privateKeyFile = path.Join(keyFolder, keyFileName) + privateExtension
 	store.publicKeyFile = path.Join(keyFolder, keyFileName) + publicExtension
+	store.nextPrivateKey = path.Join(keyFolder, nextKeyFileName) + privateExtension
+	store.nextPublicKey = path.Join(keyFolder, nextKeyFileName) + publicExtension
 	store.groupFile = path.Join(groupFolder, groupFileName)
 	store.shareFile = path.Join(groupFolder, shareFileName)
 	store.distKeyFile = path.Join(groupFolder, distKeyFileName)
+	store.historyFolder = path.Join(groupFolder, HistoryFolderName)
 	return store
 }
 
 // SaveKeyPair first saves the private key in a file with tight permissions and then
-// saves the public part in another file.
+// saves the public part in another file. Only the public part of a pair held
+// by an external signer is saved.
 func (f *fileStore) SaveKeyPair(p *Pair) error {
-	if err := Save(f.privateKeyFile, p, true); err != nil {
-		return err
+	if p.Key != nil {
+		if err := f.savePrivate(f.privateKeyFile, p); err != nil {
+			return err
+		}
 	}
 	slog.Infof("Saved the key : %s at %s", p.Public.Addr, f.publicKeyFile)
 	return Save(f.publicKeyFile, p.Public, false)
 }
 
-// LoadKeyPair decode private key first then public
+// LoadKeyPair decode private key first then public. With an external signer,
+// only the public part is loaded and must match the key of the signer.
 func (f *fileStore) LoadKeyPair() (*Pair, error) {
+	if f.signer != nil {
+		id := new(Identity)
+		if err := Load(f.publicKeyFile, id); err != nil {
+			return nil, err
+		}
+		if !id.Key.Equal(f.signer.PublicKey()) {
+			return nil, errors.New("store: the external signer holds another key than the public key file")
+		}
+		p := NewSignerPair(f.signer, id.Addr)
+		p.Public = id
+		return p, nil
+	}
 	p := new(Pair)
-	if err := Load(f.privateKeyFile, p); err != nil {
+	if err := f.loadPrivate(f.privateKeyFile, p); err != nil {
 		return nil, err
 	}
 	return p, Load(f.publicKeyFile, p.Public)
 }
 
+// SaveNextKeyPair saves the pair to the next key files, encrypted as the
+// current pair.
+func (f *fileStore) SaveNextKeyPair(p *Pair) error {
+	if f.signer != nil {
+		return errors.New("store: the key held by an external signer is rotated by the signer")
+	}
+	if err := f.savePrivate(f.nextPrivateKey, p); err != nil {
+		return err
+	}
+	slog.Infof("Saved the next key : %s at %s", p.Public.Addr, f.nextPublicKey)
+	return Save(f.nextPublicKey, p.Public, false)
+}
+
+// LoadNextKeyPair loads the pair from the next key files.
+func (f *fileStore) LoadNextKeyPair() (*Pair, error) {
+	p := new(Pair)
+	if err := f.loadPrivate(f.nextPrivateKey, p); err != nil {
+		return nil, err
+	}
+	return p, Load(f.nextPublicKey, p.Public)
+}
+
+// RotateKeyPair saves the next pair as the current one, then removes the next
+// key files. A crash in between leaves the next files, equal to the current
+// ones, which a new rotation overwrites.
+func (f *fileStore) RotateKeyPair() error {
+	p, err := f.LoadNextKeyPair()
+	if err != nil {
+		return err
+	}
+	if err := f.SaveKeyPair(p); err != nil {
+		return err
+	}
+	if err := os.Remove(f.nextPrivateKey); err != nil {
+		return err
+	}
+	return os.Remove(f.nextPublicKey)
+}
+
 func (f *fileStore) LoadGroup() (*Group, error) {
 	g := new(Group)
 	return g, Load(f.groupFile, g)
@@ -114,12 +198,12 @@ func (f *fileStore) SaveGroup(g *Group) error {
 
 func (f *fileStore) SaveShare(share *Share) error {
 	slog.Info("crypto store: saving private share in ", f.shareFile)
-	return Save(f.shareFile, share, true)
+	return f.savePrivate(f.shareFile, share)
 }
 
 func (f *fileStore) LoadShare() (*Share, error) {
 	s := new(Share)
-	return s, Load(f.shareFile, s)
+	return s, f.loadPrivate(f.shareFile, s)
 }
 
 func (f *fileStore) SaveDistPublic(d *DistPublic) error {
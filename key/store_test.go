@@ -74,3 +74,30 @@ func TestKeysSaveLoad(t *testing.T) {
 	require.Equal(t, dp.Key().String(), loadedDp.Key().String())
 
 }
+
+func TestNextKeyPair(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-key-next")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp)
+	current := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, store.SaveKeyPair(current))
+
+	_, err := store.LoadNextKeyPair()
+	require.True(t, os.IsNotExist(err))
+	require.Error(t, store.RotateKeyPair())
+
+	next := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, store.SaveNextKeyPair(next))
+	loaded, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, current.Key.String(), loaded.Key.String())
+
+	require.NoError(t, store.RotateKeyPair())
+	loaded, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, next.Key.String(), loaded.Key.String())
+	require.True(t, next.Public.Key.Equal(loaded.Public.Key))
+	_, err = store.LoadNextKeyPair()
+	require.True(t, os.IsNotExist(err))
+}
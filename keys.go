@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/dedis/drand/key"
+	"github.com/nikkolasg/slog"
+	"github.com/urfave/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Environment variables holding the passphrases of the key store, for the
+// commands run without a terminal.
+const (
+	passphraseEnv    = "DRAND_PASSPHRASE"
+	newPassphraseEnv = "DRAND_NEW_PASSPHRASE"
+)
+
+var passphraseFlag = cli.BoolFlag{
+	Name: "passphrase",
+	Usage: "Encrypt the private keys on disk with a passphrase, asked on the terminal " +
+		"or read from the " + passphraseEnv + " environment variable.",
+}
+
+var keyFileFlag = cli.StringFlag{
+	Name:  "key-file",
+	Usage: "Encrypt the private keys on disk with the content of the given file, at least 32 random bytes.",
+}
+
+var newPassphraseFlag = cli.BoolFlag{
+	Name: "new-passphrase",
+	Usage: "Encrypt the private keys with a new passphrase, asked on the terminal " +
+		"or read from the " + newPassphraseEnv + " environment variable.",
+}
+
+var newKeyFileFlag = cli.StringFlag{
+	Name:  "new-key-file",
+	Usage: "Encrypt the private keys with the content of the given file.",
+}
+
+var signerFlag = cli.StringFlag{
+	Name: "signer",
+	Usage: "Use the long-term key held by the external signer listening on the given unix socket, " +
+		"instead of the private key file. The DKG and resharing need the private key file: " +
+		"run them without --signer.",
+}
+
+var socketFlag = cli.StringFlag{
+	Name:  "socket",
+	Usage: "Unix socket the signer listens on.",
+}
+
+var applyFlag = cli.StringFlag{
+	Name: "apply",
+	Usage: "Rotate the key of the running daemon to the next key pair by resharing to the given " +
+		"group, as written by `drand key rotate`.",
+}
+
+// keyStore returns the store of the config folder, unlocked with the
+// passphrase or key file given on the command line, if any, and using the
+// external signer given on the command line, if any.
+func keyStore(c *cli.Context, folder string) key.Store {
+	var store key.Store
+	k := storeKey(c, passphraseFlag.Name, keyFileFlag.Name, passphraseEnv, "Passphrase of the key store: ")
+	if k == nil {
+		store = key.NewFileStore(folder)
+	} else {
+		store = key.NewEncryptedFileStore(folder, k)
+	}
+	if signer := externalSigner(c); signer != nil {
+		var err error
+		if store, err = key.NewSignerFileStore(store, signer); err != nil {
+			slog.Fatal(err)
+		}
+	}
+	return store
+}
+
+// externalSigner returns the external signer given on the command line, or
+// nil if none is given.
+func externalSigner(c *cli.Context) key.Signer {
+	if !c.IsSet(signerFlag.Name) {
+		return nil
+	}
+	signer, err := key.NewExternalSigner("unix", c.String(signerFlag.Name))
+	if err != nil {
+		slog.Fatalf("drand: can't reach the external signer: %s", err)
+	}
+	return signer
+}
+
+// storeKey returns the key of the store given by the flags, or nil if none is
+// set. The passphrase is read from env or asked with the prompt.
+func storeKey(c *cli.Context, passFlag, fileFlag, env, prompt string) *key.StoreKey {
+	var k *key.StoreKey
+	var err error
+	switch {
+	case c.IsSet(fileFlag):
+		k, err = key.NewKeyFileKey(c.String(fileFlag))
+	case c.Bool(passFlag):
+		k, err = key.NewPassphraseKey(readPassphrase(env, prompt))
+	default:
+		return nil
+	}
+	if err != nil {
+		slog.Fatalf("drand: can't unlock the key store: %s", err)
+	}
+	return k
+}
+
+// readPassphrase returns the passphrase in the environment variable, or asks
+// it on the terminal.
+func readPassphrase(env, prompt string) []byte {
+	if pass := os.Getenv(env); pass != "" {
+		return []byte(pass)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		slog.Fatalf("drand: can't read the passphrase: %s", err)
+	}
+	return pass
+}
+
+// checkUnlocked exits if the store could not load its private files because
+// they are encrypted and no passphrase or key file was given.
+func checkUnlocked(errs ...error) {
+	for _, err := range errs {
+		if err == key.ErrEncrypted || err == key.ErrWrongKey {
+			slog.Fatalf("drand: %s (see --passphrase and --key-file)", err)
+		}
+	}
+}
+
+// reencryptCmd encrypts the private keys of the config folder with a new
+// passphrase or key file.
+func reencryptCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	from := storeKey(c, passphraseFlag.Name, keyFileFlag.Name, passphraseEnv, "Current passphrase: ")
+	to := storeKey(c, newPassphraseFlag.Name, newKeyFileFlag.Name, newPassphraseEnv, "New passphrase: ")
+	if to == nil {
+		fatalUsage("reencrypt needs --new-passphrase or --new-key-file")
+	}
+	if err := key.Reencrypt(conf.ConfigFolder(), from, to); err != nil {
+		slog.Fatalf("drand: can't reencrypt the keys: %s", err)
+	}
+	printInfo("drand: private keys encrypted, restart the daemon with the new passphrase or key file")
+	return nil
+}
+
+// signerCmd serves the long-term key pair of the config folder over the
+// external signer protocol. It keeps the private key out of the daemon
+// process, and documents the protocol hardware signers implement.
+func signerCmd(c *cli.Context) error {
+	if !c.IsSet(socketFlag.Name) {
+		fatalUsage("signer requires a unix socket with --socket")
+	}
+	conf := contextToConfig(c)
+	pair, err := keyStore(c, conf.ConfigFolder()).LoadKeyPair()
+	checkUnlocked(err)
+	if err != nil {
+		slog.Fatalf("drand: can't load the key pair: %s", err)
+	}
+	socket := c.String(socketFlag.Name)
+	l, err := listenPrivateSocket(socket)
+	if err != nil {
+		slog.Fatalf("drand: can't listen on %s: %s", socket, err)
+	}
+	slog.Infof("drand: signer serving the key of %s on %s", pair.Public.Address(), socket)
+	return key.ServeSigner(l, pair)
+}
+
+// listenPrivateSocket listens on a unix socket at the given path that only
+// the user can connect to. The socket is created in a private directory, where
+// it is restricted, before being moved to its path, so it is never reachable
+// by others, whatever the umask.
+func listenPrivateSocket(socket string) (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(socket), ".drand-signer")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "socket")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	os.Remove(socket)
+	if err := os.Rename(tmp, socket); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// rotateCmd rotates the long-term key of the node in two steps. Without
+// --apply, it generates the next key pair, saved next to the current one, and
+// writes the group where it replaces the current key, to distribute to the
+// other nodes. With --apply, the daemon reshares to that group holding the next
+// key, while the other nodes run `drand share` with it, and the next key
+// replaces the current one once the resharing succeeded.
+func rotateCmd(c *cli.Context) error {
+	if c.IsSet(applyFlag.Name) {
+		groupPath := c.String(applyFlag.Name)
+		testEmptyGroup(groupPath)
+		client := controlClient(c)
+		printInfo("drand: rotating the key by resharing to the new group. Waiting to the end ...")
+		if _, err := client.RotateKey(groupPath, c.String(timeoutFlag.Name)); err != nil {
+			slog.Fatalf("drand: key rotation failed: %s", err)
+		}
+		printInfo("drand: long-term key rotated")
+		return nil
+	}
+
+	conf := contextToConfig(c)
+	store := keyStore(c, conf.ConfigFolder())
+	current, err := store.LoadKeyPair()
+	checkUnlocked(err)
+	if err != nil {
+		slog.Fatalf("drand: can't load the current key pair: %s", err)
+	}
+	group, err := store.LoadGroup()
+	if err != nil {
+		slog.Fatalf("drand: rotating the key needs the group of a finished DKG: %s", err)
+	}
+	if _, err := store.LoadNextKeyPair(); err == nil || err == key.ErrEncrypted || err == key.ErrWrongKey {
+		slog.Fatalf("drand: a next key pair is already pending, apply it with --apply")
+	}
+	next := key.NewKeyPairIn(group.Scheme.KeyGroup, current.Public.Addr)
+	next.Public.TLS = current.Public.TLS
+	next.Public.Transport = current.Public.Transport
+	next.Public.Name = current.Public.Name
+	next.Public.Contact = current.Public.Contact
+	next.Public.Region = current.Public.Region
+	next.Public.TLSFingerprint = current.Public.TLSFingerprint
+	if err := next.SelfSign(); err != nil {
+		slog.Fatalf("drand: could not sign the new identity: %s", err)
+	}
+	newGroup, err := group.ReplaceNode(current.Public, next.Public)
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	newGroup.TransitionTime = transitionTime(c)
+	if err := store.SaveNextKeyPair(next); err != nil {
+		slog.Fatalf("drand: can't save the next key pair: %s", err)
+	}
+	printInfo("drand: next key pair generated. Give the new group to the other nodes, " +
+		"which run `drand share <group>`, and run `drand key rotate --apply <group>` on this node.")
+	if c.IsSet(outFlag.Name) {
+		groupPath := c.String(outFlag.Name)
+		if err := key.Save(groupPath, newGroup, false); err != nil {
+			slog.Fatal(err)
+		}
+		printInfo("drand: new group written to %s", groupPath)
+		return nil
+	}
+	printData(c.App.Writer, c, newGroup)
+	return nil
+}
@@ -3,8 +3,6 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"os"
 	"path"
@@ -15,7 +13,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/dedis/drand/core"
 	"github.com/dedis/drand/fs"
 	"github.com/dedis/drand/key"
@@ -33,13 +30,12 @@ var (
 
 const gname = "group.toml"
 const dpublic = "dist_key.public"
-const defaultPort = "8080"
 
 func banner() {
-	fmt.Printf("drand %v by nikkolasg\n", version)
+	fmt.Fprintf(os.Stderr, "drand %v by nikkolasg\n", version)
 	s := "WARNING: this software has NOT received a full audit and must be \n" +
 		"used with caution and probably NOT in a production environment.\n"
-	fmt.Printf(s)
+	fmt.Fprint(os.Stderr, s)
 }
 
 var folderFlag = cli.StringFlag{
@@ -71,11 +67,32 @@ var tlsKeyFlag = cli.StringFlag{
 		"This parameter is required by default and can only be omitted if the --tls-disable flag is used.",
 }
 
+var nameFlag = cli.StringFlag{
+	Name:  "name",
+	Usage: "Name of the node, shown to the other operators in the group file.",
+}
+
+var contactFlag = cli.StringFlag{
+	Name:  "contact",
+	Usage: "Contact of the operator of the node, shown in the group file.",
+}
+
+var regionFlag = cli.StringFlag{
+	Name:  "region",
+	Usage: "Region the node runs in, shown in the group file.",
+}
+
 var insecureFlag = cli.BoolFlag{
 	Name:  "tls-disable, d",
 	Usage: "Disable TLS for all communications (not recommended).",
 }
 
+var transportFlag = cli.StringFlag{
+	Name:  "transport",
+	Value: net.TransportGRPC,
+	Usage: "Transport other nodes send their requests over, \"grpc\" or \"http\" for HTTP/1.1 when a proxy in front of the node does not carry gRPC.",
+}
+
 var controlFlag = cli.StringFlag{
 	Name:  "control",
 	Usage: "Set the port you want to listen to for control port commands. If not specified, we will use the default port 8888.",
@@ -86,16 +103,131 @@ var listenFlag = cli.StringFlag{
 	Usage: "Set the listening (binding) address. Useful if you have some kind of proxy.",
 }
 
+var publicListenFlag = cli.StringFlag{
+	Name: "public-listen",
+	Usage: "Set the listening (binding) address of the public API, over gRPC and REST. " +
+		"The listening address then only serves the protocols between drand nodes. " +
+		"If not specified, all services are served on the listening address.",
+}
+
+var publicTLSCertFlag = cli.StringFlag{
+	Name:  "public-tls-cert",
+	Usage: "Set the TLS certificate chain (in PEM format) of the public API, if it differs from --tls-cert.",
+}
+
+var publicTLSKeyFlag = cli.StringFlag{
+	Name:  "public-tls-key",
+	Usage: "Set the TLS private key (in PEM format) of the public API, if it differs from --tls-key.",
+}
+
+var publicInsecureFlag = cli.BoolFlag{
+	Name:  "public-tls-disable",
+	Usage: "Disable TLS for the public API only, for example behind a proxy terminating TLS.",
+}
+
+var restDisableFlag = cli.BoolFlag{
+	Name:  "rest-disable",
+	Usage: "Disable the public REST API. Randomness can then only be fetched over gRPC.",
+}
+
+var limitFlag = cli.StringSliceFlag{
+	Name: "limit",
+	Usage: "Limit the requests to an endpoint of the public API (public, private, distkey, group or home), " +
+		"as <endpoint>:ip=<req/s>,global=<req/s>,burst=<n>,concurrent=<n> where all limits are optional. " +
+		"The flag can be repeated for each endpoint.",
+}
+
+var nodeAuthFlag = cli.BoolFlag{
+	Name: "node-auth",
+	Usage: "Reject beacon and DKG requests that are not signed by a node of the group. " +
+		"Nodes always sign their requests, so it can be enabled on each node independently.",
+}
+
+var resetDBFlag = cli.BoolFlag{
+	Name: "reset-db",
+	Usage: "Remove the beacon database left by a previous distributed key when starting a node which has not ran the DKG yet. " +
+		"Without it, such a node refuses to start.",
+}
+
+var keepSharesFlag = cli.BoolFlag{
+	Name: "keep-shares",
+	Usage: "Keep the share of each epoch in the history of the node after a resharing, " +
+		"instead of only the groups and distributed keys. Old shares can still sign the rounds of past epochs.",
+}
+
+var epochFlag = cli.IntFlag{
+	Name:  "epoch",
+	Usage: "Show the group of the given epoch of the history of the node, numbered from 1, instead of the current one.",
+}
+
+var keepaliveFlag = cli.StringFlag{
+	Name: "keepalive",
+	Usage: "Ping the other nodes after the given duration of inactivity (e.g. 30s) and " +
+		"drop the connections whose pings are not answered. Disabled by default.",
+}
+
+var reconnectBackoffFlag = cli.StringFlag{
+	Name:  "reconnect-backoff",
+	Usage: fmt.Sprintf("Longest time to wait between two attempts to reconnect to a node. Default is %s.", core.DefaultReconnectBackoff),
+}
+
 var nodeFlag = cli.StringFlag{
 	Name:  "nodes, n",
 	Usage: "Contact the nodes at the given list of whitespace-separated addresses which have to be present in group.toml.",
 }
 
+var connectFlag = cli.StringFlag{
+	Name: "connect",
+	Usage: "Contact the node at the given address instead of the address listed in group.toml, " +
+		"for nodes serving their public API on a separate address. " +
+		"Use --nodes to select the node of the group it belongs to.",
+}
+
+var quorumFlag = cli.IntFlag{
+	Name:  "quorum",
+	Usage: "Require the given number of nodes to agree on the randomness of the round before printing it.",
+}
+
+var retriesFlag = cli.IntFlag{
+	Name:  "retries",
+	Usage: "Try all the nodes again the given number of times, with an exponential backoff, if none answers.",
+}
+
+var restFlag = cli.BoolFlag{
+	Name:  "rest",
+	Usage: "Contact the nodes over their REST API instead of gRPC.",
+}
+
 var roundFlag = cli.IntFlag{
 	Name:  "round, r",
 	Usage: "Request the public randomness generated at round num. If the drand beacon does not have the requested value, it returns an error. If not specified, the current randomness is returned.",
 }
 
+var timeFlag = cli.StringFlag{
+	Name:  "time",
+	Usage: "Request the first public randomness generated at or after the given time, either as RFC 3339 (2006-01-02T15:04:05Z07:00) or unix seconds. It can not be used with --round.",
+}
+
+var deriveFlag = cli.BoolFlag{
+	Name:  "derive",
+	Usage: "Print the 32-byte randomness derived from the beacon instead of the raw beacon.",
+}
+
+var contextFlag = cli.StringFlag{
+	Name:  "context",
+	Usage: "Context string used to derive random values from the beacon with --range and --shuffle. Different applications should use different contexts.",
+}
+
+var rangeFlag = cli.StringFlag{
+	Name:  "range",
+	Usage: "Derive from the beacon a uniform integer in [min,max), given as \"min:max\".",
+}
+
+var shuffleFlag = cli.IntFlag{
+	Name:  "shuffle",
+	Usage: "Derive from the beacon a uniform permutation of the integers [0,n).",
+}
+
 var groupFlag = cli.StringFlag{
 	Name:  "group, g",
 	Usage: "If you want to merge keys into an existing group.toml file, run the group command and specify the group.toml file with this flag.",
@@ -106,6 +238,21 @@ var certsDirFlag = cli.StringFlag{
 	Usage: "directory containing trusted certificates. Useful for testing and self signed certificates",
 }
 
+var chainHashFlag = cli.StringFlag{
+	Name:  "chain-hash",
+	Usage: "Expected chain hash of the group, as served on /api/info/group. The command fails if the group does not match it.",
+}
+
+var knownGroupFlag = cli.StringFlag{
+	Name:  "group, g",
+	Usage: "Known group file, whose nodes, threshold and round times the group fetched must have, as well as its distributed key if any.",
+}
+
+var forceGroupFlag = cli.BoolFlag{
+	Name:  "force",
+	Usage: "Overwrite the group.toml file of the current folder.",
+}
+
 var outFlag = cli.StringFlag{
 	Name: "out, o",
 	Usage: "save the requested information into a separate file" +
@@ -117,8 +264,45 @@ var periodFlag = cli.StringFlag{
 	Usage: "period to write in the group.toml file",
 }
 
+var genesisFlag = cli.StringFlag{
+	Name: "genesis",
+	Usage: "Time at which the first round is due, either as RFC 3339 (2006-01-02T15:04:05Z07:00) or unix seconds. " +
+		"The next rounds are due every period after it. Default is the time the group file is created.",
+}
+
+var requireSignedFlag = cli.BoolFlag{
+	Name:  "require-signed",
+	Usage: "Refuse the public keys that are not self-signed, as generated by older versions.",
+}
+
+var transitionFlag = cli.StringFlag{
+	Name: "transition",
+	Usage: "Time at which the group of a resharing takes over from the current one, either as RFC 3339 " +
+		"or unix seconds. All the nodes must have finished the resharing by then. " +
+		"Default is " + core.DefaultTransitionOffset.String() + " after the group file is created.",
+}
+
+var unchainedFlag = cli.BoolFlag{
+	Name: "unchained",
+	Usage: "generate randomness in unchained mode: each beacon signs only its " +
+		"round number so it can be verified without the previous beacon",
+}
+
 // XXX deleted flags : debugFlag, outFlag, groupFlag, seedFlag, periodFlag, distKeyFlag, thresholdFlag.
 
+var schemeFlag = cli.StringFlag{
+	Name: "scheme",
+	Usage: "signature scheme to write in the group.toml file: \"" + key.SchemeKyberBN256 +
+		"\" (default) or \"" + key.SchemeRFC9380BN254 + "\" to hash messages following RFC 9380",
+}
+
+var keyGroupFlag = cli.StringFlag{
+	Name: "key-group",
+	Usage: "group of the generated key: \"" + key.KeyGroupG2 + "\" (default) for short " +
+		"beacon signatures or \"" + key.KeyGroupG1 + "\" for short public keys. All the " +
+		"nodes of a group must use the same key group.",
+}
+
 var oldGroupFlag = cli.StringFlag{
 	Name: "from",
 	Usage: "Old group.toml path to specify when a new node wishes to participate " +
@@ -141,20 +325,61 @@ func main() {
 			Name:  "start",
 			Usage: "Start the drand daemon.",
 			Flags: toArray(folderFlag, tlsCertFlag, tlsKeyFlag,
-				insecureFlag, controlFlag, listenFlag, certsDirFlag),
+				insecureFlag, controlFlag, listenFlag, publicListenFlag,
+				publicTLSCertFlag, publicTLSKeyFlag, publicInsecureFlag,
+				restDisableFlag, limitFlag, nodeAuthFlag, keepSharesFlag, resetDBFlag, keepaliveFlag,
+				reconnectBackoffFlag, certsDirFlag, passphraseFlag, keyFileFlag,
+				signerFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return startCmd(c)
 			},
 		},
 		cli.Command{
-			Name:  "stop",
-			Usage: "Stop the drand daemon.\n",
+			Name: "relay",
+			Usage: "Start a relay serving the public randomness of the group. " +
+				"The relay holds no share: it follows the nodes given with --connect, " +
+				"a comma-separated list of public addresses, or --nodes, " +
+				"switching to the next one when a node fails, verifies each " +
+				"beacon against the distributed key and serves the public API " +
+				"over gRPC and REST.\n",
+			ArgsUsage: "<group.toml> group file, which must contain the distributed key",
+			Flags: toArray(folderFlag, tlsCertFlag, tlsKeyFlag, insecureFlag,
+				listenFlag, restDisableFlag, limitFlag, nodeFlag, connectFlag, keepaliveFlag,
+				reconnectBackoffFlag, certsDirFlag),
+			Action: func(c *cli.Context) error {
+				banner()
+				return relayCmd(c)
+			},
+		},
+		cli.Command{
+			Name: "stop",
+			Usage: "Stop the drand daemon gracefully: the current round is " +
+				"finished or abandoned, the connections are drained and the " +
+				"database is closed. SIGINT and SIGTERM do the same.\n",
+			Flags: toArray(controlFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return stopDaemon(c)
 			},
 		},
+		cli.Command{
+			Name:  "db",
+			Usage: "Manage the beacon database of the node.",
+			Subcommands: []cli.Command{
+				{
+					Name: "reset",
+					Usage: "Remove the beacon database, for example to start a " +
+						"new DKG with the same folder. The daemon must be " +
+						"stopped. A node which ran the DKG fetches the " +
+						"beacons again from the other nodes.\n",
+					Flags: toArray(folderFlag, controlFlag),
+					Action: func(c *cli.Context) error {
+						return resetDBCmd(c)
+					},
+				},
+			},
+		},
 		cli.Command{
 			Name: "share",
 			Usage: "Launch a sharing protocol. If one group is given as " +
@@ -166,7 +391,7 @@ func main() {
 				"this daemon start the protocol\n",
 			ArgsUsage: "<group.toml> group file",
 			Flags: toArray(folderFlag, insecureFlag, controlFlag,
-				leaderFlag, oldGroupFlag, timeoutFlag),
+				leaderFlag, oldGroupFlag, timeoutFlag, passphraseFlag, keyFileFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return shareCmd(c)
@@ -177,7 +402,8 @@ func main() {
 			Usage: "Generate the longterm keypair (drand.private, drand.public)" +
 				"for this node.\n",
 			ArgsUsage: "<address> is the public address for other nodes to contact",
-			Flags:     toArray(insecureFlag),
+			Flags: toArray(insecureFlag, tlsCertFlag, keyGroupFlag, transportFlag,
+				nameFlag, contactFlag, regionFlag, passphraseFlag, keyFileFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return keygenCmd(c)
@@ -190,7 +416,7 @@ func main() {
 				"a new group.toml file with the given identites.\n",
 			ArgsUsage: "<key1 key2 key3...> must be the identities of the group " +
 				"to create/to insert into the group",
-			Flags: toArray(groupFlag, outFlag, periodFlag),
+			Flags: toArray(groupFlag, outFlag, periodFlag, genesisFlag, transitionFlag, unchainedFlag, schemeFlag, requireSignedFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return groupCmd(c)
@@ -221,7 +447,7 @@ func main() {
 						"activated TLS in which case it prints a warning.\n",
 					ArgsUsage: "<group.toml> provides the group informations of " +
 						"the nodes that we are trying to contact.",
-					Flags: toArray(insecureFlag, tlsCertFlag, nodeFlag),
+					Flags: toArray(insecureFlag, tlsCertFlag, nodeFlag, connectFlag),
 					Action: func(c *cli.Context) error {
 						return getPrivateCmd(c)
 					},
@@ -235,24 +461,106 @@ func main() {
 						"beacon via TLS and falls back to plaintext communication " +
 						"if the contacted node has not activated TLS in which case " +
 						"it prints a warning.\n",
-					Flags: toArray(tlsCertFlag, insecureFlag, roundFlag, nodeFlag),
+					Flags: toArray(tlsCertFlag, insecureFlag, roundFlag, timeFlag,
+						nodeFlag, connectFlag, quorumFlag, retriesFlag, restFlag,
+						deriveFlag, contextFlag, rangeFlag, shuffleFlag),
 					Action: func(c *cli.Context) error {
 						return getPublicCmd(c)
 					},
 				},
+				{
+					Name: "group",
+					Usage: "Get the current group of the node at the given " +
+						"address and save it as a group file, once checked " +
+						"against the expected chain hash or the known group.\n",
+					ArgsUsage: "<address> address of the node to contact",
+					Flags: toArray(tlsCertFlag, insecureFlag, restFlag,
+						chainHashFlag, knownGroupFlag, outFlag, forceGroupFlag),
+					Action: func(c *cli.Context) error {
+						return getGroupCmd(c)
+					},
+				},
 				{
 					Name: "cokey",
 					Usage: "Get distributed public key generated during the " +
 						"DKG step.",
 					ArgsUsage: "<group.toml> provides the group informations of " +
 						"the node that we are trying to contact.",
-					Flags: toArray(tlsCertFlag, insecureFlag, nodeFlag),
+					Flags: toArray(tlsCertFlag, insecureFlag, nodeFlag, connectFlag),
 					Action: func(c *cli.Context) error {
 						return getCokeyCmd(c)
 					},
 				},
 			},
 		},
+		{
+			Name: "signer",
+			Usage: "Serve the long-term key of the node to a daemon started " +
+				"with --signer, over a unix socket. The daemon then never " +
+				"loads the private key.\n",
+			Flags: toArray(folderFlag, socketFlag, passphraseFlag, keyFileFlag),
+			Action: func(c *cli.Context) error {
+				banner()
+				return signerCmd(c)
+			},
+		},
+		{
+			Name: "backup",
+			Usage: "Save the long-term key pair, the share, the distributed " +
+				"key, the group file and optionally the beacon database of the " +
+				"node in one encrypted archive.\n",
+			ArgsUsage: "<archive> path of the archive to write",
+			Flags: toArray(folderFlag, passphraseFlag, keyFileFlag, signerFlag,
+				backupPassphraseFlag, backupKeyFileFlag, withDBFlag),
+			Action: func(c *cli.Context) error {
+				return backupCmd(c)
+			},
+		},
+		{
+			Name: "restore",
+			Usage: "Restore the content of a backup archive in the config " +
+				"folder. The archive is checked, including the share against " +
+				"the distributed key of the group, before anything is written. " +
+				"Stop the daemon first.\n",
+			ArgsUsage: "<archive> path of the archive to read",
+			Flags: toArray(folderFlag, passphraseFlag, keyFileFlag,
+				backupPassphraseFlag, backupKeyFileFlag, forceFlag),
+			Action: func(c *cli.Context) error {
+				return restoreCmd(c)
+			},
+		},
+		{
+			Name:  "key",
+			Usage: "Manage the long-term key pair and the share stored on disk.\n",
+			Subcommands: []cli.Command{
+				{
+					Name: "reencrypt",
+					Usage: "Encrypt the private keys of the node with a new passphrase " +
+						"or key file. The current ones unlock them if they are " +
+						"already encrypted. Stop the daemon first.\n",
+					Flags: toArray(folderFlag, passphraseFlag, keyFileFlag,
+						newPassphraseFlag, newKeyFileFlag),
+					Action: func(c *cli.Context) error {
+						return reencryptCmd(c)
+					},
+				},
+				{
+					Name: "rotate",
+					Usage: "Replace the long-term key pair of the node, keeping its " +
+						"place in the group. Without --apply, generate the next " +
+						"key pair and the new group holding it, to give to the " +
+						"other nodes for a resharing. With --apply, the running " +
+						"daemon takes part in the resharing with the next key, " +
+						"which replaces the current one once it succeeded. A " +
+						"threshold of the other nodes must take part.\n",
+					Flags: toArray(folderFlag, passphraseFlag, keyFileFlag,
+						outFlag, applyFlag, controlFlag, timeoutFlag, transitionFlag),
+					Action: func(c *cli.Context) error {
+						return rotateCmd(c)
+					},
+				},
+			},
+		},
 		{
 			Name:  "ping",
 			Usage: "pings the daemon checking its state\n",
@@ -284,8 +592,9 @@ func main() {
 					Name: "group",
 					Usage: "shows the current group.toml used. The group.toml " +
 						"may contain the distributed public key if the DKG has been " +
-						"ran already.\n",
-					Flags: toArray(outFlag, controlFlag),
+						"ran already. The groups of the previous epochs, before each " +
+						"resharing, are shown with --epoch.\n",
+					Flags: toArray(outFlag, controlFlag, epochFlag),
 					Action: func(c *cli.Context) error {
 						return showGroupCmd(c)
 					},
@@ -298,6 +607,15 @@ func main() {
 						return showCokeyCmd(c)
 					},
 				},
+				{
+					Name: "connections",
+					Usage: "shows the state of the connections to the other " +
+						"nodes.\n",
+					Flags: toArray(controlFlag),
+					Action: func(c *cli.Context) error {
+						return showConnectionsCmd(c)
+					},
+				},
 				{
 					Name:  "private",
 					Usage: "shows the long-term private key of a node.\n",
@@ -334,52 +652,7 @@ func main() {
 		return nil
 	}
 	if err := app.Run(os.Args); err != nil {
-		slog.Fatalf("drand: error running app: %s", err)
-	}
-}
-
-func resetBeaconDB(config *core.Config) bool {
-	if _, err := os.Stat(config.DBFolder()); err == nil {
-		// using fmt so does not get the new line at the end.
-		// XXX allow slog for that behavior
-		fmt.Printf("INCONSISTENT STATE: A beacon database exists already.\n"+
-			"drand support only one identity at the time and thus needs to delete "+
-			"the existing beacon database.\nCurrent folder is %s.\nAccept to delete "+
-			"database ? [Y/n]: ", config.DBFolder())
-		reader := bufio.NewReader(os.Stdin)
-		answer, err := reader.ReadString('\n')
-		if err != nil {
-			slog.Fatal("error reading: ", err)
-		}
-		answer = strings.ToLower(strings.TrimSpace(answer))
-		if answer != "y" {
-			slog.Print("drand: not deleting the database.")
-			return true
-		}
-
-		if err := os.RemoveAll(config.DBFolder()); err != nil {
-			slog.Fatal(err)
-		}
-		slog.Print("drand: removed existing beacon database.")
-	}
-	return false
-}
-
-func askPort() string {
-	for {
-		var port string
-		slog.Print("No valid port given. Please, choose a port number (or ENTER for default port 8080): ")
-		if _, err := fmt.Scanf("%s\n", &port); err != nil {
-			continue
-		}
-		if port == "" {
-			return defaultPort
-		}
-		_, err := strconv.Atoi(port)
-		if len(port) > 2 && len(port) < 5 && err == nil {
-			return port
-		}
-		return askPort()
+		fatalUsage("%s", err)
 	}
 }
 
@@ -393,30 +666,53 @@ func testWindows(c *cli.Context) {
 func keygenCmd(c *cli.Context) error {
 	args := c.Args()
 	if !args.Present() {
-		slog.Fatal("Missing drand address in argument")
+		fatalUsage("missing drand address in argument")
 	}
 	addr := args.First()
 	var validID = regexp.MustCompile(`[:][0-9]+$`)
 	if !validID.MatchString(addr) {
-		slog.Print("port not ok")
-		addr = addr + ":" + askPort()
+		fatalUsage("address %s has no port, give it as <host>:<port>", addr)
+	}
+	keyGroup, err := key.KeyGroupFromName(c.String(keyGroupFlag.Name))
+	if err != nil {
+		slog.Fatal(err)
 	}
-	var priv *key.Pair
+	priv := key.NewKeyPairIn(keyGroup, addr)
 	if c.Bool("tls-disable") {
-		slog.Info("Generating private / public key pair without TLS.")
-		priv = key.NewKeyPair(addr)
+		printInfo("Generating private / public key pair without TLS.")
 	} else {
-		slog.Info("Generating private / public key pair with TLS indication")
-		priv = key.NewTLSKeyPair(addr)
+		printInfo("Generating private / public key pair with TLS indication")
+		priv.Public.TLS = true
+	}
+	switch transport := c.String(transportFlag.Name); transport {
+	case net.TransportGRPC:
+	case net.TransportHTTP:
+		printInfo("Other nodes will send their requests over HTTP/1.1")
+		priv.Public.Transport = transport
+	default:
+		fatalUsage("unknown transport %q", transport)
+	}
+	priv.Public.Name = c.String(nameFlag.Name)
+	priv.Public.Contact = c.String(contactFlag.Name)
+	priv.Public.Region = c.String(regionFlag.Name)
+	if certs := strings.Fields(c.String(tlsCertFlag.Name)); len(certs) > 0 && priv.Public.TLS {
+		fingerprint, err := net.CertFingerprint(certs[0])
+		if err != nil {
+			slog.Fatalf("could not read the TLS certificate: %s", err)
+		}
+		priv.Public.TLSFingerprint = fingerprint
 	}
 
 	config := contextToConfig(c)
-	fs := key.NewFileStore(config.ConfigFolder())
+	fs := keyStore(c, config.ConfigFolder())
 
-	if _, err := fs.LoadKeyPair(); err == nil {
-		slog.Info("keypair already present. Remove them before generating new one")
+	if _, err := fs.LoadKeyPair(); err == nil || err == key.ErrEncrypted || err == key.ErrWrongKey {
+		printInfo("keypair already present. Remove them before generating new one")
 		return nil
 	}
+	if err := priv.SelfSign(); err != nil {
+		slog.Fatal("could not sign the identity: ", err)
+	}
 	if err := fs.SaveKeyPair(priv); err != nil {
 		slog.Fatal("could not save key: ", err)
 	}
@@ -425,46 +721,35 @@ func keygenCmd(c *cli.Context) error {
 	if err != nil {
 		slog.Fatal("err getting full path: ", err)
 	}
-	slog.Print("Generated keys at ", absPath)
-	slog.Print("You can copy paste the following snippet to a common group.toml file:")
-	var buff bytes.Buffer
-	buff.WriteString("[[nodes]]\n")
-	if err := toml.NewEncoder(&buff).Encode(priv.Public.TOML()); err != nil {
-		panic(err)
-	}
-	buff.WriteString("\n")
-	slog.Print(buff.String())
-	slog.Print("Or just collect all public key files and use the group command!")
+	printInfo("Generated keys at %s", absPath)
+	printInfo("You can copy paste the following public key under a [[nodes]] entry of a common group.toml file,")
+	printInfo("or just collect all public key files and use the group command!")
+	printData(c.App.Writer, c, priv.Public)
 	return nil
 }
 
 func groupCmd(c *cli.Context) error {
 	if !c.Args().Present() || (c.NArg() < 3 && !c.IsSet("group")) {
-		slog.Fatal("drand: group command take at least 3 keys as arguments")
+		fatalUsage("group command take at least 3 keys as arguments")
 	}
 	var threshold = key.DefaultThreshold(c.NArg())
 	publics := make([]*key.Identity, c.NArg())
 	for i, str := range c.Args() {
 		pub := &key.Identity{}
-		slog.Infof("drand: reading public identity from %s", str)
+		printInfo("drand: reading public identity from %s", str)
 		if err := key.Load(str, pub); err != nil {
 			slog.Fatal(err)
 		}
 		publics[i] = pub
 	}
 
-	var period = core.DefaultBeaconPeriod
-	var err error
-	if c.IsSet(periodFlag.Name) {
-		period, err = time.ParseDuration(c.String(periodFlag.Name))
-		if err != nil {
-			slog.Fatalf("drand: invalid period time given %s", err)
-		}
-	}
-
 	var group *key.Group
+	var err error
 	if c.IsSet("group") {
-		// merge with given group
+		// merge with given group, keeping the times of its rounds
+		if c.IsSet(periodFlag.Name) || c.IsSet(genesisFlag.Name) {
+			fatalUsage("--period and --genesis can not be changed when merging into a group")
+		}
 		groupPath := c.String("group")
 		testEmptyGroup(groupPath)
 		oldG := &key.Group{}
@@ -472,10 +757,47 @@ func groupCmd(c *cli.Context) error {
 			slog.Fatal(err)
 		}
 		group = oldG.MergeGroup(publics)
+		if group.Period == 0 {
+			group.Period = core.DefaultBeaconPeriod
+		}
+		group.TransitionTime = transitionTime(c)
 	} else {
+		if c.IsSet(transitionFlag.Name) {
+			fatalUsage("--transition only applies when merging into a group with --group")
+		}
 		group = key.NewGroup(publics, threshold)
+		group.Period = core.DefaultBeaconPeriod
+		if c.IsSet(periodFlag.Name) {
+			group.Period, err = time.ParseDuration(c.String(periodFlag.Name))
+			if err != nil {
+				fatalUsage("invalid period time given %s", err)
+			}
+		}
+		group.GenesisTime = time.Now().Unix()
+		if c.IsSet(genesisFlag.Name) {
+			group.GenesisTime = parseTime(c.String(genesisFlag.Name)).Unix()
+		}
+	}
+	for _, id := range group.Nodes {
+		if id.IsSigned() {
+			continue
+		}
+		if c.Bool(requireSignedFlag.Name) {
+			slog.Fatalf("drand: identity of %s is not self-signed", id.Addr)
+		}
+		printInfo("drand: identity of %s is not self-signed, its address and TLS settings can not be checked", id.Addr)
+	}
+	if c.Bool(unchainedFlag.Name) {
+		group.Unchained = true
+	}
+	schemeName := group.Scheme.Name
+	if c.IsSet(schemeFlag.Name) {
+		schemeName = c.String(schemeFlag.Name)
+	}
+	group.Scheme, err = key.SchemeFromName(schemeName, keyGroupOf(group.Nodes))
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
 	}
-	group.Period = period
 
 	if c.IsSet("out") {
 		groupPath := c.String("out")
@@ -483,21 +805,40 @@ func groupCmd(c *cli.Context) error {
 			slog.Fatal(err)
 		}
 	} else {
-		var buff bytes.Buffer
-		if err := toml.NewEncoder(&buff).Encode(group.TOML()); err != nil {
-			slog.Print("doesn't want to encode")
-		}
-		buff.WriteString("\n")
-		slog.Printf("Copy the following snippet into a new group.toml file " +
-			"and distribute it to all the participants:\n")
-		slog.Printf(buff.String())
+		printInfo("Copy the following snippet into a new group.toml file " +
+			"and distribute it to all the participants:")
+		printData(c.App.Writer, c, group)
 	}
 	return nil
 }
 
+// transitionTime returns the transition time of the group of a resharing,
+// given by --transition or after the default offset.
+func transitionTime(c *cli.Context) int64 {
+	if c.IsSet(transitionFlag.Name) {
+		return parseTime(c.String(transitionFlag.Name)).Unix()
+	}
+	return time.Now().Add(core.DefaultTransitionOffset).Unix()
+}
+
+// keyGroupOf returns the name of the group the keys of all the nodes belong to.
+func keyGroupOf(nodes []*key.Identity) string {
+	name := key.KeyGroupG2
+	if len(nodes) > 0 && key.InGroup(nodes[0].Key, key.G1) {
+		name = key.KeyGroupG1
+	}
+	g, _ := key.KeyGroupFromName(name)
+	for _, n := range nodes {
+		if !key.InGroup(n.Key, g) {
+			slog.Fatalf("drand: all keys must be on the same group, %s is not on %s", n.Addr, name)
+		}
+	}
+	return name
+}
+
 func checkGroup(c *cli.Context) error {
 	if !c.Args().Present() {
-		slog.Fatal("drand: check-group expects a group argument")
+		fatalUsage("check-group expects a group argument")
 	}
 	conf := contextToConfig(c)
 	testEmptyGroup(c.Args().First())
@@ -511,14 +852,16 @@ func checkGroup(c *cli.Context) error {
 		if err != nil {
 			slog.Fatalf("drand: error checking id %s", id.Address())
 		}
-		slog.Printf("drand: id %s answers correctly", id.Address())
+		printInfo("drand: id %s answers correctly", id.Address())
 	}
-	slog.Print("all good")
+	printInfo("all good")
 	return nil
 }
 
+// toArray returns the flags of a command, along with the flags all the
+// commands accept.
 func toArray(flags ...cli.Flag) []cli.Flag {
-	return flags
+	return append(flags, formatFlag)
 }
 
 func getGroup(c *cli.Context) *key.Group {
@@ -550,6 +893,49 @@ func contextToConfig(c *cli.Context) *core.Config {
 	if listen != "" {
 		opts = append(opts, core.WithListenAddress(listen))
 	}
+	if c.Bool(restDisableFlag.Name) {
+		opts = append(opts, core.WithRESTDisabled())
+	}
+	if c.Bool(nodeAuthFlag.Name) {
+		opts = append(opts, core.WithNodeAuth())
+	}
+	if c.Bool(keepSharesFlag.Name) {
+		opts = append(opts, core.WithShareHistory())
+	}
+	if c.Bool(resetDBFlag.Name) {
+		opts = append(opts, core.WithResetDB())
+	}
+	for _, l := range c.StringSlice(limitFlag.Name) {
+		endpoint, limit, err := parseLimit(l)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, core.WithRateLimit(endpoint, limit))
+	}
+	if publicListen := c.String(publicListenFlag.Name); publicListen != "" {
+		opts = append(opts, core.WithPublicListenAddress(publicListen))
+	} else if c.IsSet(publicTLSCertFlag.Name) || c.IsSet(publicTLSKeyFlag.Name) || c.Bool(publicInsecureFlag.Name) {
+		panic("options 'public-tls-cert', 'public-tls-key' and 'public-tls-disable' require 'public-listen'")
+	}
+	if c.Bool(publicInsecureFlag.Name) {
+		opts = append(opts, core.WithPublicInsecure())
+	} else if c.IsSet(publicTLSCertFlag.Name) || c.IsSet(publicTLSKeyFlag.Name) {
+		opts = append(opts, core.WithPublicTLS(c.String(publicTLSCertFlag.Name), c.String(publicTLSKeyFlag.Name)))
+	}
+	if c.IsSet(keepaliveFlag.Name) {
+		interval, err := time.ParseDuration(c.String(keepaliveFlag.Name))
+		if err != nil {
+			panic(fmt.Errorf("invalid keepalive: %s", err))
+		}
+		opts = append(opts, core.WithKeepalive(interval, 0))
+	}
+	if c.IsSet(reconnectBackoffFlag.Name) {
+		backoff, err := time.ParseDuration(c.String(reconnectBackoffFlag.Name))
+		if err != nil {
+			panic(fmt.Errorf("invalid reconnect backoff: %s", err))
+		}
+		opts = append(opts, core.WithReconnectBackoff(backoff))
+	}
 	port := c.String(controlFlag.Name)
 	if port != "" {
 		opts = append(opts, core.WithControlPort(port))
@@ -577,6 +963,44 @@ func contextToConfig(c *cli.Context) *core.Config {
 	return conf
 }
 
+// parseLimit reads the limits of an endpoint given to the limit flag.
+func parseLimit(s string) (string, net.Limit, error) {
+	var l net.Limit
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return "", l, fmt.Errorf("limit %q: expected <endpoint>:<limits>", s)
+	}
+	endpoint := parts[0]
+	switch endpoint {
+	case net.EndpointPublic, net.EndpointPrivate, net.EndpointDistKey, net.EndpointHome, net.EndpointGroup:
+	default:
+		return "", l, fmt.Errorf("limit %q: unknown endpoint %q", s, endpoint)
+	}
+	for _, kv := range strings.Split(parts[1], ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			return "", l, fmt.Errorf("limit %q: expected <name>=<value>", s)
+		}
+		var err error
+		switch pair[0] {
+		case "ip":
+			l.PerIP, err = strconv.ParseFloat(pair[1], 64)
+		case "global":
+			l.Global, err = strconv.ParseFloat(pair[1], 64)
+		case "burst":
+			l.Burst, err = strconv.Atoi(pair[1])
+		case "concurrent":
+			l.Concurrent, err = strconv.Atoi(pair[1])
+		default:
+			err = fmt.Errorf("unknown limit %q", pair[0])
+		}
+		if err != nil {
+			return "", l, fmt.Errorf("limit %q: %s", s, err)
+		}
+	}
+	return endpoint, l, nil
+}
+
 func getNodes(c *cli.Context) []*key.Identity {
 	group := getGroup(c)
 	var ids []*key.Identity
@@ -600,6 +1024,17 @@ func getNodes(c *cli.Context) []*key.Identity {
 	if len(ids) == 0 {
 		slog.Fatalf("drand: no nodes specified with --nodes are in the group file")
 	}
+	if c.IsSet(connectFlag.Name) {
+		// the node is contacted at its public address
+		if c.IsSet("nodes") && len(ids) != 1 {
+			fatalUsage("--connect requires --nodes to select a single node")
+		}
+		id := &key.Identity{Addr: c.String(connectFlag.Name), TLS: !c.Bool("tls-disable")}
+		if c.IsSet("nodes") {
+			id.Key = ids[0].Key
+		}
+		return []*key.Identity{id}
+	}
 	return ids
 }
 
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	gnet "net"
 	"os"
@@ -14,9 +17,11 @@ import (
 	"github.com/dedis/drand/core"
 	"github.com/dedis/drand/fs"
 	"github.com/dedis/drand/key"
+	"github.com/dedis/drand/protobuf/drand"
 	"github.com/dedis/drand/test"
 	"github.com/kabukky/httpscerts"
 	"github.com/nikkolasg/slog"
+	"github.com/urfave/cli"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/share"
@@ -40,6 +45,11 @@ func TestKeyGen(t *testing.T) {
 	out, err := cmd.Output()
 	require.Nil(t, err)
 	fmt.Println(string(out))
+	// only the public key is printed on stdout
+	pub := new(key.PublicTOML)
+	_, err = toml.Decode(string(out), pub)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:8081", pub.Address)
 	config := core.NewConfig(core.WithConfigFolder(tmp))
 	fs := key.NewFileStore(config.ConfigFolder())
 	priv, err := fs.LoadKeyPair()
@@ -96,6 +106,37 @@ func TestGroup(t *testing.T) {
 	require.True(t, strings.Contains(string(out), expectedOut))
 	require.Nil(t, err)
 
+	//test only the group is printed on stdout
+	args = []string{"drand", "--folder", tmpPath, "group", "--format", "json"}
+	args = append(args, names...)
+	cmd = exec.Command(args[0], args[1:]...)
+	out, err = cmd.Output()
+	require.NoError(t, err)
+	printed := new(key.GroupTOML)
+	require.NoError(t, json.Unmarshal(out, printed))
+	require.Len(t, printed.Nodes, n)
+	require.Equal(t, key.DefaultThreshold(n), printed.Threshold)
+
+	//test refusing unsigned keys
+	args = []string{"drand", "--folder", tmpPath, "group", "--require-signed"}
+	args = append(args, names...)
+	cmd = exec.Command(args[0], args[1:]...)
+	out, err = cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.Error(t, err)
+	args = []string{"drand", "--folder", tmpPath, "group", "--require-signed"}
+	for i := 0; i < 3; i++ {
+		signedName := path.Join(tmpPath, fmt.Sprintf("drand-signed-%d.public", i))
+		signedPriv := key.NewKeyPair("127.0.0.1")
+		require.NoError(t, signedPriv.SelfSign())
+		require.NoError(t, key.Save(signedName, signedPriv.Public, false))
+		args = append(args, signedName)
+	}
+	cmd = exec.Command(args[0], args[1:]...)
+	out, err = cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.Nil(t, err)
+
 	//recreates exactly like in main and saves the group
 	var threshold = key.DefaultThreshold(n)
 	publics := make([]*key.Identity, n)
@@ -213,7 +254,7 @@ func TestStartWithoutGroup(t *testing.T) {
 	go main()
 
 	initDKGCmd := exec.Command("drand", "share")
-	out, err := initDKGCmd.Output()
+	out, err := initDKGCmd.CombinedOutput()
 	expectedErr := "needs at least one group.toml file argument"
 	output := string(out)
 	require.Error(t, err)
@@ -397,3 +438,86 @@ func TestClientTLS(t *testing.T) {
 	require.True(t, strings.Contains(string(out), expectedOutput))
 	require.NoError(t, err)
 }
+
+func TestParseLimit(t *testing.T) {
+	endpoint, limit, err := parseLimit("private:ip=0.5,global=10,burst=2,concurrent=4")
+	require.NoError(t, err)
+	require.Equal(t, "private", endpoint)
+	require.Equal(t, 0.5, limit.PerIP)
+	require.Equal(t, 10.0, limit.Global)
+	require.Equal(t, 2, limit.Burst)
+	require.Equal(t, 4, limit.Concurrent)
+
+	endpoint, _, err = parseLimit("group:ip=1")
+	require.NoError(t, err)
+	require.Equal(t, "group", endpoint)
+
+	_, _, err = parseLimit("beacon:ip=1")
+	require.Error(t, err)
+	_, _, err = parseLimit("public:rate=1")
+	require.Error(t, err)
+	_, _, err = parseLimit("public")
+	require.Error(t, err)
+}
+
+func TestSameGroup(t *testing.T) {
+	_, known := test.BatchIdentities(5)
+	known.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{known.Nodes[0].Key}}
+	// the group served holds copies of the nodes of the known group
+	served := func() *key.Group {
+		g := known.MergeGroup(nil)
+		for i, id := range g.Nodes {
+			n := *id
+			g.Nodes[i] = &n
+		}
+		g.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{known.Nodes[0].Key}}
+		return g
+	}
+	require.NoError(t, sameGroup(known, served()))
+
+	g := served()
+	g.Threshold--
+	require.Error(t, sameGroup(known, g))
+	g = served()
+	g.Nodes[1].Addr = "127.0.0.1:1"
+	require.Error(t, sameGroup(known, g))
+	g = served()
+	g.Nodes = g.Nodes[1:]
+	require.Error(t, sameGroup(known, g))
+
+	// the distributed key is only checked when known
+	known.PublicKey = &key.DistPublic{Coefficients: []kyber.Point{known.Nodes[1].Key}}
+	require.Error(t, sameGroup(known, served()))
+	known.PublicKey = nil
+	require.NoError(t, sameGroup(known, served()))
+}
+
+func TestPrintData(t *testing.T) {
+	printed := func(format string, v interface{}) string {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		set.String(formatFlag.Name, format, "")
+		var buff bytes.Buffer
+		printData(&buff, cli.NewContext(cli.NewApp(), set, nil), v)
+		return buff.String()
+	}
+
+	resp := &drand.PublicRandResponse{Round: 12, Previous: []byte{0xab, 0xcd}}
+	out := printed(formatText, resp)
+	require.Contains(t, out, "previous: abcd\n")
+	require.Contains(t, out, "round: 12\n")
+	require.NotContains(t, out, "{")
+	require.Contains(t, printed(formatJSON, resp), `"round": 12`)
+	out = printed(formatTOML, resp)
+	require.Contains(t, out, `previous = "abcd"`)
+	require.Contains(t, out, `round = 12`)
+	require.NotContains(t, out, `12.0`)
+
+	_, group := test.BatchIdentities(3)
+	out = printed(formatText, group)
+	require.Contains(t, out, "[[Nodes]]")
+	loaded := new(key.GroupTOML)
+	_, err := toml.Decode(out, loaded)
+	require.NoError(t, err)
+	require.Equal(t, group.Threshold, loaded.Threshold)
+	require.Contains(t, printed(formatJSON, group), fmt.Sprintf(`"Threshold": %d`, group.Threshold))
+}
@@ -0,0 +1,131 @@
+package net
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Internal requests, the ones of the Beacon and Dkg services, are
+// authenticated with the long-term keys of the nodes: the caller signs the
+// method, its own address, the current time and the hash of the request, and
+// sends them alongside the request as gRPC metadata. The callee checks that the
+// caller belongs to one of its groups and that the signature is valid, recent
+// and covers the request it received. The signature does not depend on the
+// address used to reach the callee, so it can only be replayed, unchanged and
+// for MaxAuthDelay, to the other nodes the caller could send it to itself.
+const (
+	authAddrKey = "drand-auth-addr"
+	authTimeKey = "drand-auth-time"
+	authSigKey  = "drand-auth-sig-bin"
+)
+
+// MaxAuthDelay is the maximum difference allowed between the time of an
+// authenticated request and the clock of the node receiving it.
+const MaxAuthDelay = 1 * time.Minute
+
+// AuthVerifier checks that the node at the given address is allowed to call
+// internal methods and that sig is its signature of msg.
+type AuthVerifier func(addr string, msg, sig []byte) error
+
+// isInternal returns true if the gRPC method belongs to the services used
+// between drand nodes.
+func isInternal(method string) bool {
+	return strings.HasPrefix(method, "/drand.Beacon/") || strings.HasPrefix(method, "/dkg.Dkg/")
+}
+
+// authMessage returns the message signed to authenticate a request: the
+// method, the address of the caller, the time and the SHA-256 hash of the
+// protobuf encoding of the request.
+func authMessage(method, from string, t int64, req interface{}) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, errors.New("drand: request is not a protobuf message")
+	}
+	buff, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(buff)
+	return []byte(fmt.Sprintf("drand-auth\n%s\n%s\n%d\n%x", method, from, t, h)), nil
+}
+
+// AuthClientInterceptor returns an interceptor authenticating the internal
+// requests sent by the node at the given address. sign returns the signature
+// of a message under the long-term key of the node.
+func AuthClientInterceptor(addr string, sign func(msg []byte) ([]byte, error)) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !isInternal(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		now := time.Now().Unix()
+		msg, err := authMessage(method, addr, now, req)
+		if err != nil {
+			return err
+		}
+		sig, err := sign(msg)
+		if err != nil {
+			return err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			authAddrKey, addr,
+			authTimeKey, strconv.FormatInt(now, 10),
+			authSigKey, string(sig))
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// authServerInterceptor returns an interceptor rejecting the internal requests
+// that are not authenticated by a node accepted by the verifier.
+func authServerInterceptor(verify AuthVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isInternal(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		from, ts, sig := md.Get(authAddrKey), md.Get(authTimeKey), md.Get(authSigKey)
+		if len(from) != 1 || len(ts) != 1 || len(sig) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "drand: internal request without authentication")
+		}
+		t, err := strconv.ParseInt(ts[0], 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "drand: invalid authentication time")
+		}
+		if delay := time.Since(time.Unix(t, 0)); delay > MaxAuthDelay || delay < -MaxAuthDelay {
+			return nil, status.Error(codes.Unauthenticated, "drand: authentication time too far from local time")
+		}
+		msg, err := authMessage(info.FullMethod, from[0], t, req)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err := verify(from[0], msg, []byte(sig[0])); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "drand: request from %s rejected: %s", from[0], err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// chainUnaryServer returns an interceptor calling the given interceptors in
+// order before the handler.
+func chainUnaryServer(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, h)
+			}
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,79 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestListenerNodeAuth(t *testing.T) {
+	addr := "127.0.0.1:4006"
+	peer := &testPeer{addr, false}
+	member := "127.0.0.1:5000"
+
+	// a keyed hash stands in for the signature of the long-term key
+	sign := func(from string) func([]byte) ([]byte, error) {
+		return func(msg []byte) ([]byte, error) {
+			h := sha256.Sum256(append([]byte(from), msg...))
+			return h[:], nil
+		}
+	}
+	verify := func(from string, msg, sig []byte) error {
+		if from != member {
+			return errors.New("unknown node")
+		}
+		expected, _ := sign(from)(msg)
+		if !bytes.Equal(expected, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	lis := NewTCPGrpcListener(addr, &DefaultService{R: &testRandomnessServer{42}}, WithNodeAuth(verify))
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient(grpc.WithUnaryInterceptor(AuthClientInterceptor(member, sign(member))))
+	_, err := client.NewBeacon(peer, &drand.BeaconRequest{})
+	require.NoError(t, err)
+
+	// public requests need no authentication
+	anonymous := NewGrpcClient()
+	resp, err := anonymous.Public(peer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), resp.GetRound())
+	_, err = anonymous.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Error(t, err)
+
+	stranger := NewGrpcClient(grpc.WithUnaryInterceptor(AuthClientInterceptor("127.0.0.1:5001", sign("127.0.0.1:5001"))))
+	_, err = stranger.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Error(t, err)
+
+	impostor := NewGrpcClient(grpc.WithUnaryInterceptor(AuthClientInterceptor(member, sign("127.0.0.1:5001"))))
+	_, err = impostor.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Error(t, err)
+
+	// the authentication does not depend on the address used to reach the node
+	_, err = client.NewBeacon(&testPeer{"localhost:4006", false}, &drand.BeaconRequest{})
+	require.NoError(t, err)
+
+	// but it covers the request: a signature can not be reused for another one
+	auth := AuthClientInterceptor(member, sign(member))
+	tamper := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		swap := func(ctx context.Context, method string, _, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return invoker(ctx, method, &drand.BeaconRequest{Round: 2}, reply, cc, opts...)
+		}
+		return auth(ctx, method, req, reply, cc, swap, opts...)
+	}
+	tampered := NewGrpcClient(grpc.WithUnaryInterceptor(tamper))
+	_, err = tampered.NewBeacon(peer, &drand.BeaconRequest{Round: 1})
+	require.Error(t, err)
+}
@@ -1,7 +1,10 @@
 package net
 
 import (
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 
@@ -43,3 +46,24 @@ func (p *CertManager) Add(certPath string) error {
 	slog.Infof("peer cert: storing server certificate %s", certPath)
 	return nil
 }
+
+// CertFingerprint returns the hex-encoded SHA-256 hash of the DER encoding of
+// the first certificate of the PEM file at the given path, as listed in the
+// group file to identify the certificate of a node.
+func CertFingerprint(certPath string) (string, error) {
+	b, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return "", err
+	}
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return "", fmt.Errorf("peer cert: no certificate in %s", certPath)
+		}
+		if block.Type == "CERTIFICATE" {
+			h := sha256.Sum256(block.Bytes)
+			return hex.EncodeToString(h[:]), nil
+		}
+	}
+}
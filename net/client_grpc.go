@@ -2,8 +2,7 @@ package net
 
 import (
 	"context"
-	"errors"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,7 +10,10 @@ import (
 	"github.com/dedis/drand/protobuf/drand"
 	"github.com/nikkolasg/slog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 )
 
 // Service holds all functionalities that a drand node should implement
@@ -29,19 +31,45 @@ var defaultJSONMarshaller = &HexJSON{}
 // using gRPC as its underlying mechanism
 type grpcClient struct {
 	sync.Mutex
-	conns    map[string]*grpc.ClientConn
+	conns    map[string]*peerConn
 	opts     []grpc.DialOption
 	timeout  time.Duration
 	manager  *CertManager
 	failFast grpc.CallOption
 }
 
+// peerConn is the connection to a peer with the statistics reported by
+// Connections.
+type peerConn struct {
+	conn       *grpc.ClientConn
+	tls        bool
+	failures   int
+	lastErr    error
+	reconnects int
+}
+
+// ConnStatus describes the connection of a client to a peer.
+type ConnStatus struct {
+	Address string
+	TLS     bool
+	// State is the connectivity state of the connection, or "CLOSED" if it
+	// has been evicted and not dialed again yet.
+	State string
+	// Failures is the number of consecutive failed calls to the peer.
+	Failures int
+	// LastError is the error of the last failed call, if any.
+	LastError error
+	// Reconnects is the number of times the connection was evicted and dialed
+	// again.
+	Reconnects int
+}
+
 // NewGrpcClient returns an implementation of an InternalClient  and
 // ExternalClient using gRPC connections
 func NewGrpcClient(opts ...grpc.DialOption) *grpcClient {
 	return &grpcClient{
 		opts:     opts,
-		conns:    make(map[string]*grpc.ClientConn),
+		conns:    make(map[string]*peerConn),
 		manager:  NewCertManager(),
 		timeout:  1 * time.Second,
 		failFast: grpc.FailFast(true),
@@ -64,163 +92,245 @@ func NewGrpcClientWithTimeout(timeout time.Duration, opts ...grpc.DialOption) *g
 	return c
 }
 
-func (g *grpcClient) getTimeoutContext() context.Context {
-	clientDeadline := time.Now().Add(g.timeout)
-	ctx, _ := context.WithDeadline(context.Background(), clientDeadline)
-	return ctx
+// timeoutOption carries the deadline of a single call.
+type timeoutOption struct {
+	grpc.EmptyCallOption
+	timeout time.Duration
+}
+
+// WithCallTimeout sets the time a call of the InternalClient can take,
+// overriding the default timeout of the client.
+func WithCallTimeout(d time.Duration) CallOption {
+	return &timeoutOption{timeout: d}
+}
+
+// callContext returns the context of an internal call, with the deadline
+// given by the options or the default timeout of the client.
+func (g *grpcClient) callContext(opts []CallOption) (context.Context, context.CancelFunc) {
+	g.Lock()
+	timeout := g.timeout
+	g.Unlock()
+	return timeoutContext(timeout, opts)
+}
+
+// timeoutContext returns a context with the deadline given by the options, or
+// the given timeout if none is set.
+func timeoutContext(timeout time.Duration, opts []CallOption) (context.Context, context.CancelFunc) {
+	for _, opt := range opts {
+		if t, ok := opt.(*timeoutOption); ok {
+			timeout = t.timeout
+		}
+	}
+	return context.WithTimeout(context.Background(), timeout)
 }
 
+// SetTimeout sets the default timeout of the internal calls.
 func (g *grpcClient) SetTimeout(p time.Duration) {
+	g.Lock()
+	defer g.Unlock()
 	g.timeout = p
 }
 
 func (g *grpcClient) Public(p Peer, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
 	var resp *drand.PublicRandResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := drand.NewRandomnessClient(c)
-		resp, err = client.Public(context.Background(), in)
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		resp, err = drand.NewRandomnessClient(c).Public(context.Background(), in)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
 }
 
 func (g *grpcClient) Private(p Peer, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
 	var resp *drand.PrivateRandResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := drand.NewRandomnessClient(c)
-		resp, err = client.Private(context.Background(), in)
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		resp, err = drand.NewRandomnessClient(c).Private(context.Background(), in)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
 }
 
 func (g *grpcClient) DistKey(p Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
 	var resp *drand.DistKeyResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := drand.NewInfoClient(c)
-		resp, err = client.DistKey(context.Background(), in)
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		resp, err = drand.NewInfoClient(c).DistKey(context.Background(), in)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
+}
+
+func (g *grpcClient) Group(p Peer, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	var resp *drand.GroupResponse
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		resp, err = drand.NewInfoClient(c).Group(context.Background(), in)
+		return err
+	})
+	return resp, err
 }
 
 func (g *grpcClient) Setup(p Peer, in *dkg.DKGPacket, opts ...CallOption) (*dkg.DKGResponse, error) {
 	var resp *dkg.DKGResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := dkg.NewDkgClient(c)
-		resp, err = client.Setup(g.getTimeoutContext(), in, grpc.FailFast(true))
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		ctx, cancel := g.callContext(opts)
+		defer cancel()
+		resp, err = dkg.NewDkgClient(c).Setup(ctx, in, append(opts, g.failFast)...)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
 }
 
 func (g *grpcClient) Reshare(p Peer, in *dkg.ResharePacket, opts ...CallOption) (*dkg.ReshareResponse, error) {
 	var resp *dkg.ReshareResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := dkg.NewDkgClient(c)
-		resp, err = client.Reshare(g.getTimeoutContext(), in, grpc.FailFast(true))
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		ctx, cancel := g.callContext(opts)
+		defer cancel()
+		resp, err = dkg.NewDkgClient(c).Reshare(ctx, in, append(opts, g.failFast)...)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
 }
 
 func (g *grpcClient) NewBeacon(p Peer, in *drand.BeaconRequest, opts ...CallOption) (*drand.BeaconResponse, error) {
 	var resp *drand.BeaconResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := drand.NewBeaconClient(c)
-		resp, err = client.NewBeacon(g.getTimeoutContext(), in, append(opts, grpc.FailFast(true))...)
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		ctx, cancel := g.callContext(opts)
+		defer cancel()
+		resp, err = drand.NewBeaconClient(c).NewBeacon(ctx, in, append(opts, g.failFast)...)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
+	})
+	return resp, err
 }
 
 func (g *grpcClient) Home(p Peer, in *drand.HomeRequest, opts ...CallOption) (*drand.HomeResponse, error) {
 	var resp *drand.HomeResponse
-	fn := func() error {
-		c, err := g.conn(p)
-		if err != nil {
-			return err
-		}
-		client := drand.NewInfoClient(c)
-		resp, err = client.Home(context.Background(), in, opts...)
+	err := g.call(p, func(c *grpc.ClientConn) (err error) {
+		resp, err = drand.NewInfoClient(c).Home(context.Background(), in, opts...)
 		return err
-	}
-	return resp, g.retryTLS(p, fn)
-
+	})
+	return resp, err
 }
 
-// retryTLS performs a manual reconnection in case there is an error with TLS
-// certificates. It's a hack for issue
+// call runs fn on the connection to the peer. If the peer is unavailable, the
+// connection is evicted and fn is tried once more on a fresh connection. This
+// recovers from connections stuck in their reconnection backoff after a
+// network failure, and from TLS errors that gRPC does not recover from, see
 // https://github.com/grpc/grpc-go/issues/2394
-func (g *grpcClient) retryTLS(p Peer, fn func() error) error {
-	for retry := 0; retry < 3; retry++ {
-		err := fn()
-		if err == nil {
-			return nil
+func (g *grpcClient) call(p Peer, fn func(*grpc.ClientConn) error) error {
+	var err error
+	for retry := 0; retry < 2; retry++ {
+		var c *grpc.ClientConn
+		if c, err = g.conn(p); err != nil {
+			return err
 		}
-		isTLS := strings.Contains(err.Error(), "tls:")
-		isX509 := strings.Contains(err.Error(), "x509:")
-		if isTLS || isX509 {
-			slog.Infof("drand: forced client reconnection due to TLS error to %s", p.Address())
-			g.deleteConn(p)
-			g.conn(p)
-		} else {
-			// not an TLS error
+		err = fn(c)
+		g.report(p, err)
+		if status.Code(err) != codes.Unavailable {
 			return err
 		}
+		slog.Infof("grpc-client: evicting connection to %s: %s", p.Address(), err)
+		g.evict(p)
+	}
+	return err
+}
+
+// report records the outcome of a call to the peer.
+func (g *grpcClient) report(p Peer, err error) {
+	g.Lock()
+	defer g.Unlock()
+	pc, ok := g.conns[p.Address()]
+	if !ok {
+		return
 	}
-	return errors.New("grpc: can't connect to " + p.Address())
+	if err == nil {
+		pc.failures = 0
+		return
+	}
+	pc.failures++
+	pc.lastErr = err
 }
 
-func (g *grpcClient) deleteConn(p Peer) {
+// evict closes the connection to the peer, so the next call dials it again.
+func (g *grpcClient) evict(p Peer) {
 	g.Lock()
 	defer g.Unlock()
-	delete(g.conns, p.Address())
+	if pc, ok := g.conns[p.Address()]; ok && pc.conn != nil {
+		pc.conn.Close()
+		pc.conn = nil
+	}
 }
 
-// conn retrieve an already existing conn to the given peer or create a new one
+// conn retrieve an already existing conn to the given peer or create a new
+// one. Connections that are shut down or use another TLS setting than the peer
+// are replaced.
 func (g *grpcClient) conn(p Peer) (*grpc.ClientConn, error) {
 	g.Lock()
 	defer g.Unlock()
-	var err error
-	c, ok := g.conns[p.Address()]
+	pc, ok := g.conns[p.Address()]
 	if !ok {
-		slog.Debugf("grpc-client: attempting connection to %s (TLS %v)", p.Address(), p.IsTLS())
-		if !p.IsTLS() {
-			c, err = grpc.Dial(p.Address(), append(g.opts, grpc.WithInsecure())...)
-		} else {
-			pool := g.manager.Pool()
-			creds := credentials.NewClientTLSFromCert(pool, "")
-			opts := append(g.opts, grpc.WithTransportCredentials(creds))
-			c, err = grpc.Dial(p.Address(), opts...)
+		pc = &peerConn{}
+		g.conns[p.Address()] = pc
+	}
+	if pc.conn != nil && (pc.tls != p.IsTLS() || pc.conn.GetState() == connectivity.Shutdown) {
+		pc.conn.Close()
+		pc.conn = nil
+	}
+	if pc.conn != nil {
+		return pc.conn, nil
+	}
+	if ok {
+		pc.reconnects++
+	}
+	slog.Debugf("grpc-client: attempting connection to %s (TLS %v)", p.Address(), p.IsTLS())
+	var c *grpc.ClientConn
+	var err error
+	if !p.IsTLS() {
+		c, err = grpc.Dial(p.Address(), append(g.opts, grpc.WithInsecure())...)
+	} else {
+		pool := g.manager.Pool()
+		creds := credentials.NewClientTLSFromCert(pool, "")
+		opts := append(g.opts, grpc.WithTransportCredentials(creds))
+		c, err = grpc.Dial(p.Address(), opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pc.conn, pc.tls = c, p.IsTLS()
+	return c, nil
+}
+
+// Connections returns the status of the connections to all the peers
+// contacted so far, sorted by address.
+func (g *grpcClient) Connections() []ConnStatus {
+	g.Lock()
+	defer g.Unlock()
+	var list []ConnStatus
+	for addr, pc := range g.conns {
+		s := ConnStatus{
+			Address:    addr,
+			TLS:        pc.tls,
+			State:      "CLOSED",
+			Failures:   pc.failures,
+			LastError:  pc.lastErr,
+			Reconnects: pc.reconnects,
+		}
+		if pc.conn != nil {
+			s.State = pc.conn.GetState().String()
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Address < list[j].Address })
+	return list
+}
+
+// Close closes all the connections of the client.
+func (g *grpcClient) Close() {
+	g.Lock()
+	defer g.Unlock()
+	for _, pc := range g.conns {
+		if pc.conn != nil {
+			pc.conn.Close()
+			pc.conn = nil
 		}
-		g.conns[p.Address()] = c
 	}
-	return c, err
 }
 
 // proxyClient is used by the gRPC json gateway to dispatch calls to the
@@ -246,3 +356,7 @@ func (p *proxyClient) DistKey(c context.Context, in *drand.DistKeyRequest, opts
 func (p *proxyClient) Home(c context.Context, in *drand.HomeRequest, opts ...grpc.CallOption) (*drand.HomeResponse, error) {
 	return p.s.Home(c, in)
 }
+
+func (p *proxyClient) Group(c context.Context, in *drand.GroupRequest, opts ...grpc.CallOption) (*drand.GroupResponse, error) {
+	return p.s.Group(c, in)
+}
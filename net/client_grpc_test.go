@@ -0,0 +1,56 @@
+package net
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type slowBeaconServer struct {
+	delay time.Duration
+}
+
+func (s *slowBeaconServer) NewBeacon(c context.Context, in *drand.BeaconRequest) (*drand.BeaconResponse, error) {
+	time.Sleep(s.delay)
+	return &drand.BeaconResponse{}, nil
+}
+
+func TestGrpcClientConnections(t *testing.T) {
+	addr := "127.0.0.1:4007"
+	peer := &testPeer{addr, false}
+	client := NewGrpcClientWithTimeout(time.Second)
+
+	// the connection to an unreachable peer is evicted, so the peer is dialed
+	// again as soon as it is back instead of after the reconnection backoff
+	_, err := client.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	conns := client.Connections()
+	require.Len(t, conns, 1)
+	require.Equal(t, addr, conns[0].Address)
+	require.Equal(t, "CLOSED", conns[0].State)
+	require.Equal(t, 2, conns[0].Failures)
+	require.Equal(t, 1, conns[0].Reconnects)
+
+	lis := NewTCPGrpcListener(addr, &DefaultService{B: &slowBeaconServer{200 * time.Millisecond}})
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+	_, err = client.NewBeacon(peer, &drand.BeaconRequest{})
+	require.NoError(t, err)
+	conns = client.Connections()
+	require.Equal(t, "READY", conns[0].State)
+	require.Equal(t, 0, conns[0].Failures)
+	require.Equal(t, 2, conns[0].Reconnects)
+
+	// the deadline of a single call overrides the default timeout
+	_, err = client.NewBeacon(peer, &drand.BeaconRequest{}, WithCallTimeout(50*time.Millisecond))
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	conns = client.Connections()
+	require.Equal(t, "READY", conns[0].State)
+	require.Equal(t, 1, conns[0].Failures)
+}
@@ -0,0 +1,224 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dedis/drand/protobuf/dkg"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// internalPrefix is the path prefix of the internal methods served over the
+// HTTP transport: a request to internalPrefix + "/drand.Beacon/NewBeacon" is
+// the POST of the protobuf encoding of a BeaconRequest.
+const internalPrefix = "/internal"
+
+// statusHeader carries the gRPC code of the errors returned over the HTTP
+// transport, the body of the response being the message of the error.
+const statusHeader = "Drand-Status"
+
+// maxInternalSize is the maximum size of the requests and responses of the
+// HTTP transport, the default limit of gRPC.
+const maxInternalSize = 4 << 20
+
+// httpClient implements InternalClient by sending the requests as protobuf
+// messages over HTTP/1.1, see TransportHTTP. The requests are authenticated
+// like the gRPC ones, the authentication being sent in HTTP headers.
+type httpClient struct {
+	sync.Mutex
+	manager *CertManager
+	addr    string
+	sign    func([]byte) ([]byte, error)
+	timeout time.Duration
+	peers   map[string]*httpPeer
+}
+
+// httpPeer is the client to a peer with the statistics reported by
+// Connections.
+type httpPeer struct {
+	client   *http.Client
+	tls      bool
+	failures int
+	lastErr  error
+}
+
+// NewHTTPClient returns an InternalClient using the HTTP transport. Requests
+// are authenticated with sign, the signature under the long-term key of the
+// node at the given address, if not nil. Certificates of the peers using TLS
+// are checked against the given trust store.
+func NewHTTPClient(c *CertManager, addr string, sign func(msg []byte) ([]byte, error)) InternalClient {
+	return &httpClient{
+		manager: c,
+		addr:    addr,
+		sign:    sign,
+		timeout: 1 * time.Second,
+		peers:   make(map[string]*httpPeer),
+	}
+}
+
+func (h *httpClient) NewBeacon(p Peer, in *drand.BeaconRequest, opts ...CallOption) (*drand.BeaconResponse, error) {
+	resp := new(drand.BeaconResponse)
+	return resp, h.call(p, "/drand.Beacon/NewBeacon", in, resp, opts)
+}
+
+func (h *httpClient) Setup(p Peer, in *dkg.DKGPacket, opts ...CallOption) (*dkg.DKGResponse, error) {
+	resp := new(dkg.DKGResponse)
+	return resp, h.call(p, "/dkg.Dkg/Setup", in, resp, opts)
+}
+
+func (h *httpClient) Reshare(p Peer, in *dkg.ResharePacket, opts ...CallOption) (*dkg.ReshareResponse, error) {
+	resp := new(dkg.ReshareResponse)
+	return resp, h.call(p, "/dkg.Dkg/Reshare", in, resp, opts)
+}
+
+// SetTimeout sets the default timeout of the internal calls.
+func (h *httpClient) SetTimeout(d time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+	h.timeout = d
+}
+
+// call posts the request to the method of the peer and decodes the response
+// in out. Errors are returned with the gRPC code given by the peer, or
+// Unavailable if the peer could not be reached.
+func (h *httpClient) call(p Peer, method string, in, out proto.Message, opts []CallOption) error {
+	buff, err := proto.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", restAddr(p)+internalPrefix+method, bytes.NewReader(buff))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", MIMEProtobuf)
+	if h.sign != nil {
+		now := time.Now().Unix()
+		msg, err := authMessage(method, h.addr, now, in)
+		if err != nil {
+			return err
+		}
+		sig, err := h.sign(msg)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(authAddrKey, h.addr)
+		req.Header.Set(authTimeKey, strconv.FormatInt(now, 10))
+		req.Header.Set(authSigKey, base64.StdEncoding.EncodeToString(sig))
+	}
+	client, timeout, err := h.client(p)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := timeoutContext(timeout, opts)
+	defer cancel()
+	err = h.do(ctx, client, req, out)
+	h.report(p, err)
+	return err
+}
+
+func (h *httpClient) do(ctx context.Context, client *http.Client, req *http.Request, out proto.Message) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxInternalSize))
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		code := codes.Unknown
+		if c, err := strconv.Atoi(resp.Header.Get(statusHeader)); err == nil {
+			code = codes.Code(c)
+		}
+		return status.Error(code, strings.TrimSpace(string(body)))
+	}
+	return proto.Unmarshal(body, out)
+}
+
+// client returns the HTTP client to the peer, creating it if needed, and the
+// default timeout of the calls.
+func (h *httpClient) client(p Peer) (*http.Client, time.Duration, error) {
+	h.Lock()
+	defer h.Unlock()
+	hp, ok := h.peers[p.Address()]
+	if ok && hp.tls == p.IsTLS() {
+		return hp.client, h.timeout, nil
+	}
+	client := &http.Client{}
+	if p.IsTLS() {
+		host, _, err := net.SplitHostPort(p.Address())
+		if err != nil {
+			return nil, 0, err
+		}
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    h.manager.Pool(),
+				ServerName: host,
+			},
+		}
+	}
+	if !ok {
+		hp = &httpPeer{}
+		h.peers[p.Address()] = hp
+	}
+	hp.client, hp.tls = client, p.IsTLS()
+	return client, h.timeout, nil
+}
+
+// report records the outcome of a call to the peer.
+func (h *httpClient) report(p Peer, err error) {
+	h.Lock()
+	defer h.Unlock()
+	hp, ok := h.peers[p.Address()]
+	if !ok {
+		return
+	}
+	if err == nil {
+		hp.failures = 0
+		return
+	}
+	hp.failures++
+	hp.lastErr = err
+}
+
+// Connections returns the status of the peers contacted so far, sorted by
+// address. HTTP connections are not kept open, so the state of a peer is
+// READY if its last call succeeded and TRANSIENT_FAILURE otherwise.
+func (h *httpClient) Connections() []ConnStatus {
+	h.Lock()
+	defer h.Unlock()
+	var list []ConnStatus
+	for addr, hp := range h.peers {
+		s := ConnStatus{
+			Address:   addr,
+			TLS:       hp.tls,
+			State:     "READY",
+			Failures:  hp.failures,
+			LastError: hp.lastErr,
+		}
+		if hp.failures > 0 {
+			s.State = "TRANSIENT_FAILURE"
+		}
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Address < list[j].Address })
+	return list
+}
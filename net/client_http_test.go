@@ -0,0 +1,75 @@
+package net
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type transportPeer struct {
+	testPeer
+	transport string
+}
+
+func (t *transportPeer) PeerTransport() string {
+	return t.transport
+}
+
+func TestHTTPTransport(t *testing.T) {
+	addr := "127.0.0.1:4008"
+	peer := &transportPeer{testPeer{addr, false}, TransportHTTP}
+	member := "127.0.0.1:5000"
+
+	sign := func(from string) func([]byte) ([]byte, error) {
+		return func(msg []byte) ([]byte, error) {
+			h := sha256.Sum256(append([]byte(from), msg...))
+			return h[:], nil
+		}
+	}
+	verify := func(from string, msg, sig []byte) error {
+		expected, _ := sign(member)(msg)
+		if from != member || !bytes.Equal(expected, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+
+	lis := NewTCPGrpcListener(addr, &DefaultService{B: &slowBeaconServer{200 * time.Millisecond}}, WithNodeAuth(verify))
+	go lis.Start()
+	defer lis.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewHTTPClient(NewCertManager(), member, sign(member))
+	_, err := client.NewBeacon(peer, &drand.BeaconRequest{Round: 1})
+	require.NoError(t, err)
+
+	// the gRPC code of the errors is kept
+	stranger := NewHTTPClient(NewCertManager(), "127.0.0.1:5001", sign("127.0.0.1:5001"))
+	_, err = stranger.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	anonymous := NewHTTPClient(NewCertManager(), member, nil)
+	_, err = anonymous.NewBeacon(peer, &drand.BeaconRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.NewBeacon(peer, &drand.BeaconRequest{}, WithCallTimeout(50*time.Millisecond))
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	conns := client.Connections()
+	require.Len(t, conns, 1)
+	require.Equal(t, "TRANSIENT_FAILURE", conns[0].State)
+	require.Equal(t, 1, conns[0].Failures)
+
+	// peers are contacted over the transport they declare
+	transports := NewTransportClient(map[string]InternalClient{TransportHTTP: client})
+	_, err = transports.NewBeacon(peer, &drand.BeaconRequest{})
+	require.NoError(t, err)
+	_, err = transports.NewBeacon(&testPeer{addr, false}, &drand.BeaconRequest{})
+	require.Error(t, err)
+	require.Equal(t, "READY", transports.Connections()[0].State)
+}
@@ -40,7 +40,10 @@ func (r *restClient) Public(p Peer, in *drand.PublicRandRequest) (*drand.PublicR
 	var req *http.Request
 	var err error
 	basePath := base + "/api/public"
-	if in.GetRound() == 0 {
+	if in.GetRound() == 0 && in.GetTime() != 0 {
+		url := fmt.Sprintf("%s?time=%d", basePath, in.GetTime())
+		req, err = http.NewRequest("GET", url, nil)
+	} else if in.GetRound() == 0 {
 		// then simple GET method
 		req, err = http.NewRequest("GET", basePath, nil)
 	} else {
@@ -88,6 +91,9 @@ func (r *restClient) DistKey(p Peer, in *drand.DistKeyRequest) (*drand.DistKeyRe
 		return nil, err
 	}
 	url := base + "/api/info/distkey"
+	if in.GetRound() != 0 {
+		url += fmt.Sprintf("?round=%d", in.GetRound())
+	}
 	req, err := http.NewRequest("GET", url, bytes.NewBuffer(buff))
 	if err != nil {
 		return nil, err
@@ -101,6 +107,19 @@ func (r *restClient) DistKey(p Peer, in *drand.DistKeyRequest) (*drand.DistKeyRe
 
 }
 
+func (r *restClient) Group(p Peer, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	req, err := http.NewRequest("GET", restAddr(p)+"/api/info/group", nil)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := r.doRequest(p, req)
+	if err != nil {
+		return nil, err
+	}
+	drandResponse := new(drand.GroupResponse)
+	return drandResponse, r.marshaller.Unmarshal(respBody, drandResponse)
+}
+
 func (r *restClient) doRequest(remote Peer, req *http.Request) ([]byte, error) {
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
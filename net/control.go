@@ -35,9 +35,9 @@ func (g *ControlListener) Start() {
 	}
 }
 
-// Stop the listener and connections
+// Stop the listener and connections, once the pending commands are answered.
 func (g *ControlListener) Stop() {
-	g.conns.Stop()
+	g.conns.GracefulStop()
 }
 
 //ControlClient is a struct that implement control.ControlClient and is used to
@@ -118,6 +118,35 @@ func (c *ControlClient) Group() (*control.GroupResponse, error) {
 	return c.client.Group(context.Background(), &control.GroupRequest{})
 }
 
+// GroupAt returns the group of the given epoch of the history of the node.
+func (c *ControlClient) GroupAt(epoch int) (*control.GroupResponse, error) {
+	return c.client.Group(context.Background(), &control.GroupRequest{Epoch: uint32(epoch)})
+}
+
+// Connections returns the status of the connections of the drand node to the
+// other nodes.
+func (c *ControlClient) Connections() (*control.ConnectionsResponse, error) {
+	return c.client.Connections(context.Background(), &control.ConnectionsRequest{})
+}
+
+// RotateKey asks the drand daemon to replace its long-term key with its next
+// key pair, by resharing to the group at the given path. It waits until the
+// resharing is finished.
+func (c *ControlClient) RotateKey(newPath string, timeout string) (*control.RotateKeyResponse, error) {
+	request := &control.RotateKeyRequest{
+		New: &control.GroupInfo{
+			Location: &control.GroupInfo_Path{Path: newPath},
+		},
+		Timeout: timeout,
+	}
+	return c.client.RotateKey(context.Background(), request)
+}
+
+// Shutdown asks the drand daemon to stop gracefully.
+func (c *ControlClient) Shutdown() (*control.ShutdownResponse, error) {
+	return c.client.Shutdown(context.Background(), &control.ShutdownRequest{})
+}
+
 func controlListenAddr(port string) string {
 	return fmt.Sprintf("%s:%s", "localhost", port)
 }
@@ -66,3 +66,10 @@ func (s *DefaultService) Home(c context.Context, in *drand.HomeRequest) (*drand.
 	}
 	return s.I.Home(c, in)
 }
+
+func (s *DefaultService) Group(c context.Context, in *drand.GroupRequest) (*drand.GroupResponse, error) {
+	if s.I == nil {
+		return &drand.GroupResponse{}, nil
+	}
+	return s.I.Group(c, in)
+}
@@ -16,8 +16,11 @@ import (
 // acts as a listener to receive incoming requests and acts a client connecting
 // to drand particpants.
 // The gateway fixes all drand functionalities offered by drand.
+// If PublicListener is set, it serves the public API while Listener only
+// serves the protocols between drand nodes.
 type Gateway struct {
 	Listener
+	PublicListener Listener
 	InternalClient
 	ControlListener
 }
@@ -26,6 +29,7 @@ type ExternalClient interface {
 	Public(p Peer, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error)
 	Private(p Peer, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error)
 	DistKey(p Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error)
+	Group(p Peer, in *drand.GroupRequest) (*drand.GroupResponse, error)
 }
 
 type CallOption = grpc.CallOption
@@ -37,7 +41,11 @@ type InternalClient interface {
 	NewBeacon(p Peer, in *drand.BeaconRequest, opts ...CallOption) (*drand.BeaconResponse, error)
 	Setup(p Peer, in *dkg.DKGPacket, opts ...CallOption) (*dkg.DKGResponse, error)
 	Reshare(p Peer, in *dkg.ResharePacket, opts ...CallOption) (*dkg.ReshareResponse, error)
+	// SetTimeout sets the default timeout of the calls, which WithCallTimeout
+	// overrides for a single call.
 	SetTimeout(time.Duration)
+	// Connections returns the status of the connections to the peers.
+	Connections() []ConnStatus
 }
 
 // Listener is the active listener for incoming requests.
@@ -47,16 +55,23 @@ type Listener interface {
 	Stop()
 }
 
-func NewGrpcGatewayInsecure(listen string, port string, s Service, cs control.ControlServer, opts ...grpc.DialOption) Gateway {
+// NewGrpcGatewayInsecure returns a gateway listening and contacting other
+// nodes without TLS. The listener options configure the services it serves.
+func NewGrpcGatewayInsecure(listen string, port string, s Service, cs control.ControlServer, lopts []ListenerOption, opts ...grpc.DialOption) Gateway {
 	return Gateway{
 		InternalClient:  NewGrpcClient(opts...),
-		Listener:        NewTCPGrpcListener(listen, s),
+		Listener:        NewTCPGrpcListener(listen, s, lopts...),
 		ControlListener: NewTCPGrpcControlListener(cs, port),
 	}
 }
 
-func NewGrpcGatewayFromCertManager(listen string, port string, certPath, keyPath string, certs *CertManager, s Service, cs control.ControlServer, opts ...grpc.DialOption) Gateway {
-	l, err := NewTLSGrpcListener(listen, certPath, keyPath, s, grpc.ConnectionTimeout(500*time.Millisecond))
+// NewGrpcGatewayFromCertManager returns a gateway listening over TLS with the
+// given certificate and trusting the certificates of the manager when
+// contacting other nodes. The listener options configure the services it
+// serves.
+func NewGrpcGatewayFromCertManager(listen string, port string, certPath, keyPath string, certs *CertManager, s Service, cs control.ControlServer, lopts []ListenerOption, opts ...grpc.DialOption) Gateway {
+	lopts = append(lopts, WithServerOptions(grpc.ConnectionTimeout(500*time.Millisecond)))
+	l, err := NewTLSGrpcListener(listen, certPath, keyPath, s, lopts...)
 	if err != nil {
 		panic(err)
 	}
@@ -70,9 +85,15 @@ func NewGrpcGatewayFromCertManager(listen string, port string, certPath, keyPath
 func (g Gateway) StartAll() {
 	go g.ControlListener.Start()
 	go g.Listener.Start()
+	if g.PublicListener != nil {
+		go g.PublicListener.Start()
+	}
 }
 
 func (g Gateway) StopAll() {
 	g.Listener.Stop()
+	if g.PublicListener != nil {
+		g.PublicListener.Stop()
+	}
 	g.ControlListener.Stop()
 }
@@ -42,8 +42,6 @@ func (t *testRandomnessServer) Private(context.Context, *drand.PrivateRandReques
 func TestListener(t *testing.T) {
 	addr1 := "127.0.0.1:4000"
 	peer1 := &testPeer{addr1, false}
-	addr2 := "127.0.0.1:4100"
-	peer2 := &testPeer{addr2, false}
 	randServer := &testRandomnessServer{42}
 
 	lis1 := NewTCPGrpcListener(addr1, &DefaultService{R: randServer})
@@ -58,12 +56,64 @@ func TestListener(t *testing.T) {
 	require.Equal(t, expected.GetRound(), resp.GetRound())
 
 	rest := NewRestClient()
-	resp, err = rest.Public(peer2, &drand.PublicRandRequest{})
+	resp, err = rest.Public(peer1, &drand.PublicRandRequest{})
 	require.NoError(t, err)
 	expected = &drand.PublicRandResponse{Round: randServer.round}
 	require.Equal(t, expected.GetRound(), resp.GetRound())
 }
 
+func TestListenerWithoutREST(t *testing.T) {
+	addr1 := "127.0.0.1:4003"
+	peer1 := &testPeer{addr1, false}
+	randServer := &testRandomnessServer{42}
+
+	lis1 := NewTCPGrpcListener(addr1, &DefaultService{R: randServer}, WithoutREST())
+	go lis1.Start()
+	defer lis1.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient()
+	resp, err := client.Public(peer1, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, randServer.round, resp.GetRound())
+
+	rest := NewRestClient()
+	_, err = rest.Public(peer1, &drand.PublicRandRequest{})
+	require.Error(t, err)
+}
+
+func TestListenerPublicPrivate(t *testing.T) {
+	privAddr := "127.0.0.1:4004"
+	privPeer := &testPeer{privAddr, false}
+	pubAddr := "127.0.0.1:4005"
+	pubPeer := &testPeer{pubAddr, false}
+	service := &DefaultService{R: &testRandomnessServer{42}}
+
+	private := NewTCPGrpcListener(privAddr, service, PrivateServicesOnly())
+	go private.Start()
+	defer private.Stop()
+	public := NewTCPGrpcListener(pubAddr, service, PublicServicesOnly())
+	go public.Start()
+	defer public.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewGrpcClient()
+	resp, err := client.Public(pubPeer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), resp.GetRound())
+	_, err = NewRestClient().Public(pubPeer, &drand.PublicRandRequest{})
+	require.NoError(t, err)
+	_, err = client.NewBeacon(pubPeer, &drand.BeaconRequest{})
+	require.Error(t, err)
+
+	_, err = client.NewBeacon(privPeer, &drand.BeaconRequest{})
+	require.NoError(t, err)
+	_, err = client.Public(privPeer, &drand.PublicRandRequest{})
+	require.Error(t, err)
+	_, err = NewRestClient().Public(privPeer, &drand.PublicRandRequest{})
+	require.Error(t, err)
+}
+
 // ref https://bbengfort.github.io/programmer/2017/03/03/secure-grpc.html
 func TestListenerTLS(t *testing.T) {
 	if run.GOOS == "windows" {
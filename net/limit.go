@@ -0,0 +1,248 @@
+package net
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the endpoints of the public API that can be rate limited. They
+// designate the same method over gRPC and REST.
+const (
+	EndpointPublic  = "public"
+	EndpointPrivate = "private"
+	EndpointDistKey = "distkey"
+	EndpointHome    = "home"
+	EndpointGroup   = "group"
+)
+
+// Limit holds the limits applied to the requests of one endpoint. A zero value
+// means no limit.
+type Limit struct {
+	// PerIP is the number of requests per second accepted from each client IP.
+	PerIP float64
+	// Global is the number of requests per second accepted from all clients.
+	Global float64
+	// Burst is the number of requests accepted at once above the rates. It
+	// defaults to the rate rounded up.
+	Burst int
+	// Concurrent is the maximum number of requests processed at the same time.
+	Concurrent int
+}
+
+// ErrRateLimited is returned when a request exceeds the rate limits of its
+// endpoint.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// ErrTooManyRequests is returned when an endpoint already processes its
+// maximum number of concurrent requests.
+var ErrTooManyRequests = errors.New("too many concurrent requests")
+
+// RateLimiter enforces the limits of the endpoints of the public API, on both
+// the gRPC and REST paths. It is safe for concurrent use.
+type RateLimiter struct {
+	endpoints map[string]*endpointLimiter
+}
+
+// NewRateLimiter returns a RateLimiter enforcing the given limits, indexed by
+// endpoint name. Endpoints without limits accept all requests.
+func NewRateLimiter(limits map[string]Limit) *RateLimiter {
+	r := &RateLimiter{endpoints: make(map[string]*endpointLimiter)}
+	for name, l := range limits {
+		e := &endpointLimiter{
+			limit:  l,
+			global: newBucket(l.Global, l.Burst),
+			perIP:  make(map[string]*bucket),
+		}
+		if l.Concurrent > 0 {
+			e.running = make(chan bool, l.Concurrent)
+		}
+		r.endpoints[name] = e
+	}
+	return r
+}
+
+// Acquire reserves the processing of a request to the given endpoint from the
+// given client IP. It returns ErrRateLimited or ErrTooManyRequests if the
+// request must be rejected. Otherwise, the returned function must be called
+// once the request is processed.
+func (r *RateLimiter) Acquire(endpoint, ip string) (func(), error) {
+	e, ok := r.endpoints[endpoint]
+	if !ok {
+		return func() {}, nil
+	}
+	return e.acquire(ip, time.Now())
+}
+
+// sweepPeriod is the interval at which the buckets of idle clients are
+// deleted.
+const sweepPeriod = time.Minute
+
+type endpointLimiter struct {
+	sync.Mutex
+	limit     Limit
+	global    *bucket
+	perIP     map[string]*bucket
+	lastSweep time.Time
+	running   chan bool
+}
+
+func (e *endpointLimiter) acquire(ip string, now time.Time) (func(), error) {
+	// the concurrency is checked first so the requests rejected for it do not
+	// consume the rate of their client
+	release := func() {}
+	if e.running != nil {
+		select {
+		case e.running <- true:
+			release = func() { <-e.running }
+		default:
+			return nil, ErrTooManyRequests
+		}
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	if now.Sub(e.lastSweep) > sweepPeriod {
+		for k, b := range e.perIP {
+			if b.refill(now) {
+				delete(e.perIP, k)
+			}
+		}
+		e.lastSweep = now
+	}
+	b, ok := e.perIP[ip]
+	if !ok {
+		b = newBucket(e.limit.PerIP, e.limit.Burst)
+		if e.limit.PerIP > 0 {
+			e.perIP[ip] = b
+		}
+	}
+	b.refill(now)
+	e.global.refill(now)
+	if !b.available() || !e.global.available() {
+		release()
+		return nil, ErrRateLimited
+	}
+	b.take()
+	e.global.take()
+	return release, nil
+}
+
+// bucket is a token bucket refilled at a constant rate. A bucket with a zero
+// rate never runs out of tokens.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	b := &bucket{rate: rate, burst: float64(burst)}
+	if burst <= 0 {
+		b.burst = math.Max(1, math.Ceil(rate))
+	}
+	b.tokens = b.burst
+	return b
+}
+
+// refill adds the tokens accumulated since the last refill. It returns true if
+// the bucket is full.
+func (b *bucket) refill(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	return b.tokens >= b.burst
+}
+
+func (b *bucket) available() bool {
+	return b.rate == 0 || b.tokens >= 1
+}
+
+func (b *bucket) take() {
+	if b.rate != 0 {
+		b.tokens--
+	}
+}
+
+// grpcEndpoint returns the endpoint name of a gRPC method of the public API,
+// or an empty string for other methods.
+func grpcEndpoint(method string) string {
+	if !strings.HasPrefix(method, "/drand.Randomness/") && !strings.HasPrefix(method, "/drand.Info/") {
+		return ""
+	}
+	return strings.ToLower(method[strings.LastIndex(method, "/")+1:])
+}
+
+// restEndpoint returns the endpoint name of a path of the REST API.
+func restEndpoint(path string) string {
+	switch {
+	case path == "/api/public" || strings.HasPrefix(path, "/api/public/"):
+		return EndpointPublic
+	case path == "/api/private":
+		return EndpointPrivate
+	case path == "/api/info/distkey":
+		return EndpointDistKey
+	case path == "/api/info/group":
+		return EndpointGroup
+	case path == "/api":
+		return EndpointHome
+	}
+	return ""
+}
+
+// hostOf returns the IP part of a network address.
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// unaryInterceptor returns an interceptor rejecting the gRPC requests exceeding
+// the limits with the ResourceExhausted code.
+func (r *RateLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		endpoint := grpcEndpoint(info.FullMethod)
+		if endpoint == "" {
+			return handler(ctx, req)
+		}
+		var ip string
+		if p, ok := peer.FromContext(ctx); ok {
+			ip = hostOf(p.Addr.String())
+		}
+		release, err := r.Acquire(endpoint, ip)
+		if err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// httpHandler returns a handler rejecting the REST requests exceeding the
+// limits with the 429 status code before passing them to the given handler.
+func (r *RateLimiter) httpHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		release, err := r.Acquire(restEndpoint(req.URL.Path), hostOf(req.RemoteAddr))
+		if err != nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		defer release()
+		h.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,92 @@
+package net
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterPerIP(t *testing.T) {
+	r := NewRateLimiter(map[string]Limit{EndpointPrivate: {PerIP: 2}})
+	e := r.endpoints[EndpointPrivate]
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		_, err := e.acquire("1.1.1.1", now)
+		require.NoError(t, err)
+	}
+	_, err := e.acquire("1.1.1.1", now)
+	require.Equal(t, ErrRateLimited, err)
+	// other clients have their own limit
+	_, err = e.acquire("2.2.2.2", now)
+	require.NoError(t, err)
+	// the bucket refills at 2 requests per second
+	_, err = e.acquire("1.1.1.1", now.Add(500*time.Millisecond))
+	require.NoError(t, err)
+	_, err = e.acquire("1.1.1.1", now.Add(500*time.Millisecond))
+	require.Equal(t, ErrRateLimited, err)
+
+	// endpoints without limits accept everything
+	for i := 0; i < 10; i++ {
+		_, err := r.Acquire(EndpointPublic, "1.1.1.1")
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimiterGlobal(t *testing.T) {
+	r := NewRateLimiter(map[string]Limit{EndpointPublic: {Global: 1, Burst: 3}})
+	e := r.endpoints[EndpointPublic]
+	now := time.Now()
+
+	for _, ip := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		_, err := e.acquire(ip, now)
+		require.NoError(t, err)
+	}
+	_, err := e.acquire("4.4.4.4", now)
+	require.Equal(t, ErrRateLimited, err)
+	_, err = e.acquire("4.4.4.4", now.Add(time.Second))
+	require.NoError(t, err)
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	r := NewRateLimiter(map[string]Limit{EndpointPrivate: {Concurrent: 2}})
+	release1, err := r.Acquire(EndpointPrivate, "1.1.1.1")
+	require.NoError(t, err)
+	_, err = r.Acquire(EndpointPrivate, "1.1.1.1")
+	require.NoError(t, err)
+	_, err = r.Acquire(EndpointPrivate, "2.2.2.2")
+	require.Equal(t, ErrTooManyRequests, err)
+	release1()
+	_, err = r.Acquire(EndpointPrivate, "2.2.2.2")
+	require.NoError(t, err)
+
+	// requests rejected for the concurrency do not consume the rate
+	r = NewRateLimiter(map[string]Limit{EndpointPrivate: {PerIP: 1, Concurrent: 1}})
+	e := r.endpoints[EndpointPrivate]
+	now := time.Now()
+	release, err := e.acquire("1.1.1.1", now)
+	require.NoError(t, err)
+	_, err = e.acquire("2.2.2.2", now)
+	require.Equal(t, ErrTooManyRequests, err)
+	release()
+	release, err = e.acquire("2.2.2.2", now)
+	require.NoError(t, err)
+	release()
+	// and requests rejected for the rate do not keep a slot
+	_, err = e.acquire("2.2.2.2", now)
+	require.Equal(t, ErrRateLimited, err)
+	_, err = e.acquire("1.1.1.1", now.Add(time.Second))
+	require.NoError(t, err)
+}
+
+func TestRateLimiterEndpoints(t *testing.T) {
+	require.Equal(t, EndpointPublic, grpcEndpoint("/drand.Randomness/Public"))
+	require.Equal(t, EndpointDistKey, grpcEndpoint("/drand.Info/DistKey"))
+	require.Equal(t, EndpointGroup, grpcEndpoint("/drand.Info/Group"))
+	require.Equal(t, "", grpcEndpoint("/drand.Beacon/NewBeacon"))
+	require.Equal(t, EndpointPublic, restEndpoint("/api/public/12"))
+	require.Equal(t, EndpointPrivate, restEndpoint("/api/private"))
+	require.Equal(t, EndpointHome, restEndpoint("/api"))
+	require.Equal(t, EndpointGroup, restEndpoint("/api/info/group"))
+}
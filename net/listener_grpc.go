@@ -5,207 +5,262 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dedis/drand/protobuf/dkg"
 	"github.com/dedis/drand/protobuf/drand"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/nikkolasg/slog"
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
-// grpcInsecureListener implements Listener using gRPC connections and regular HTTP
-// connections for the JSON REST API.
-// NOTE: This use cmux under the hood to be able to use non-tls connection. The
-// reason of this relatively high costs (multiple routines etc) is described in
-// the issue https://github.com/grpc/grpc-go/issues/555.
-type grpcInsecureListener struct {
-	Service
-	grpcServer *grpc.Server
-	restServer *http.Server
-	grpcLis    net.Listener
-	httpLis    net.Listener
-}
-
-// getHttpAddress retrieves a unique port for an http request handler. This port is 100 ports above the port
-// used for the gRPC handler.
-func getHttpAddress(addrGrpc string) string {
-	// Gets the last value seperated by ":". This is to avoid getting tripped up by IPv6 addresses.
-	addrComponents := strings.Split(addrGrpc, ":")
-	portGrpc := addrComponents[len(addrComponents)-1]
-	portHttpInt, err := strconv.ParseUint(portGrpc, 10, 16)
-	if err != nil {
-		panic("Error when converting gRPC port to uint16")
-	}
-	portHttp := strconv.FormatUint(portHttpInt+100, 10)
-	addrComponents[len(addrComponents)-1] = portHttp
-	return strings.Join(addrComponents, ":")
+// ShutdownTimeout is the time a listener being stopped waits for the requests
+// in progress to finish.
+const ShutdownTimeout = 5 * time.Second
+
+// ListenerOption configures the services and the REST API served by a
+// Listener.
+type ListenerOption func(*listenerConfig)
+
+type listenerConfig struct {
+	restDisabled bool
+	grpcOpts     []grpc.ServerOption
+	interceptors []grpc.UnaryServerInterceptor
+	limiter      *RateLimiter
+	latestMaxAge func() time.Duration
+	public       bool
+	private      bool
 }
 
-// NewTCPGrpcListener returns a gRPC listener using plain TCP connections
-// without TLS. The listener will bind to the given address:port
-// tuple.
-func NewTCPGrpcListener(addrGrpc string, s Service, opts ...grpc.ServerOption) Listener {
-	// Setup listener for gRPC first
-	grpcLis, err := net.Listen("tcp", addrGrpc)
-	if err != nil {
-		panic("tcp listener: " + err.Error())
+// WithRateLimiter applies the limits of the given RateLimiter to the requests
+// of the public API, over gRPC and REST.
+func WithRateLimiter(r *RateLimiter) ListenerOption {
+	return func(c *listenerConfig) {
+		c.limiter = r
+		c.interceptors = append(c.interceptors, r.unaryInterceptor())
 	}
+}
 
-	// Setup listener for http requests; this is run on one port number above the gRPC handler.
-	addrHttp := getHttpAddress(addrGrpc)
-	httpLis, err := net.Listen("tcp", addrHttp)
-	if err != nil {
-		panic("tcp listener: " + err.Error())
+// WithLatestMaxAge sets the function returning how long the latest beacon
+// served by the REST API can be cached, usually until the next round. Without
+// it, clients must revalidate the latest beacon at each request.
+func WithLatestMaxAge(fn func() time.Duration) ListenerOption {
+	return func(c *listenerConfig) {
+		c.latestMaxAge = fn
 	}
+}
 
-	// grpc API
-	grpcServer := grpc.NewServer(opts...)
-
-	// REST api
-	o := runtime.WithMarshalerOption("*", defaultJSONMarshaller)
-	gwMux := runtime.NewServeMux(o)
-	proxyClient := newProxyClient(s)
-	ctx := context.TODO()
-	if err := drand.RegisterRandomnessHandlerClient(ctx, gwMux, proxyClient); err != nil {
-		panic(err)
-	}
-	if err = drand.RegisterInfoHandlerClient(ctx, gwMux, proxyClient); err != nil {
-		panic(err)
-	}
-	restRouter := http.NewServeMux()
-	newHandler := func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		gwMux.ServeHTTP(w, r)
+// WithNodeAuth rejects the requests of the Beacon and Dkg services that are not
+// authenticated by a node accepted by the verifier, see
+// AuthClientInterceptor.
+func WithNodeAuth(verify AuthVerifier) ListenerOption {
+	return func(c *listenerConfig) {
+		c.interceptors = append(c.interceptors, authServerInterceptor(verify))
 	}
+}
 
-	restRouter.Handle("/", http.HandlerFunc(newHandler))
-	restServer := &http.Server{
-		Handler: restRouter,
+// PublicServicesOnly restricts the listener to the services meant for clients:
+// the Randomness and Info services, over gRPC and REST.
+func PublicServicesOnly() ListenerOption {
+	return func(c *listenerConfig) {
+		c.private = false
 	}
+}
 
-	g := &grpcInsecureListener{
-		Service:    s,
-		grpcServer: grpcServer,
-		restServer: restServer,
-		grpcLis:    grpcLis,
-		httpLis:    httpLis,
+// PrivateServicesOnly restricts the listener to the services used between
+// drand nodes: the Beacon and Dkg services. It does not serve the REST API.
+func PrivateServicesOnly() ListenerOption {
+	return func(c *listenerConfig) {
+		c.public = false
+		c.restDisabled = true
 	}
-	drand.RegisterRandomnessServer(g.grpcServer, g.Service)
-	drand.RegisterBeaconServer(g.grpcServer, g.Service)
-	drand.RegisterInfoServer(g.grpcServer, g.Service)
-	dkg.RegisterDkgServer(g.grpcServer, g.Service)
-	return g
 }
 
-func (g *grpcInsecureListener) Start() {
-	go g.grpcServer.Serve(g.grpcLis)
-	go g.restServer.Serve(g.httpLis)
+// WithoutREST disables the REST API: the listener only serves gRPC requests.
+func WithoutREST() ListenerOption {
+	return func(c *listenerConfig) {
+		c.restDisabled = true
+	}
 }
 
-func (g *grpcInsecureListener) Stop() {
-	g.grpcLis.Close()
-	g.httpLis.Close()
-	g.restServer.Shutdown(context.Background())
-	g.grpcServer.Stop()
+// WithServerOptions applies the given options to the gRPC server.
+func WithServerOptions(opts ...grpc.ServerOption) ListenerOption {
+	return func(c *listenerConfig) {
+		c.grpcOpts = append(c.grpcOpts, opts...)
+	}
 }
 
-type grpcTLSListener struct {
+// grpcListener implements Listener using gRPC connections and regular HTTP
+// connections for the JSON REST API. By default both are served on the same
+// port: the connections whose first request is a gRPC one are served by the
+// gRPC server itself, so its keepalive settings apply, and the other ones by
+// the HTTP server. HTTP/2 connections are accepted in cleartext (h2c) as the
+// TLS connections, if any, are terminated by the listener. By default, a
+// listener serves all the services of drand; PublicServicesOnly and
+// PrivateServicesOnly allow to expose the public API and the protocols between
+// nodes on different addresses.
+type grpcListener struct {
 	Service
-	server     *http.Server
 	grpcServer *grpc.Server
-	// tls listener
-	l net.Listener
+	server     *http.Server
+	mux        cmux.CMux
+	grpcL      net.Listener
+	httpL      net.Listener
 }
 
-// NewTLSGrpcListener brings...
-func NewTLSGrpcListener(bindingAddr string, certPath, keyPath string, s Service, opts ...grpc.ServerOption) (Listener, error) {
-	lis, err := net.Listen("tcp", bindingAddr)
+// NewTCPGrpcListener returns a gRPC listener using plain TCP connections
+// without TLS. The listener will bind to the given address:port
+// tuple.
+func NewTCPGrpcListener(addr string, s Service, opts ...ListenerOption) Listener {
+	l, err := newGrpcListener(addr, nil, s, opts...)
 	if err != nil {
-		return nil, err
+		panic("tcp listener: " + err.Error())
 	}
+	return l
+}
 
+// NewTLSGrpcListener returns a gRPC listener accepting only TLS connections
+// using the given certificate and private key. The listener will bind to the
+// given address:port tuple.
+func NewTLSGrpcListener(bindingAddr string, certPath, keyPath string, s Service, opts ...ListenerOption) (Listener, error) {
 	x509KeyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
 	if err != nil {
 		return nil, err
 	}
-
-	grpcCreds, err := credentials.NewServerTLSFromFile(certPath, keyPath)
-	if err != nil {
-		return nil, err
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{x509KeyPair},
+		NextProtos:   []string{"h2", "http/1.1"},
 	}
-	serverOpts := append(opts, grpc.Creds(grpcCreds))
-	grpcServer := grpc.NewServer(serverOpts...)
-	drand.RegisterRandomnessServer(grpcServer, s)
-	drand.RegisterInfoServer(grpcServer, s)
-	drand.RegisterBeaconServer(grpcServer, s)
-	dkg.RegisterDkgServer(grpcServer, s)
-
-	o := runtime.WithMarshalerOption("*", defaultJSONMarshaller)
-	gwMux := runtime.NewServeMux(o)
-	proxy := &drandProxy{s, s}
-	err = drand.RegisterRandomnessHandlerClient(context.Background(), gwMux, proxy)
-	if err != nil {
-		return nil, err
+	return newGrpcListener(bindingAddr, tlsConfig, s, opts...)
+}
+
+func newGrpcListener(addr string, tlsConfig *tls.Config, s Service, opts ...ListenerOption) (*grpcListener, error) {
+	conf := &listenerConfig{public: true, private: true}
+	for _, opt := range opts {
+		opt(conf)
 	}
-	err = drand.RegisterInfoHandlerClient(context.Background(), gwMux, proxy)
-	if err != nil {
-		return nil, err
+	if !conf.public {
+		conf.restDisabled = true
 	}
 
-	mux := http.NewServeMux()
-	mux.Handle("/", gwMux)
-	server := &http.Server{
-		Handler: grpcHandlerFunc(grpcServer, mux),
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{x509KeyPair},
-			NextProtos:   []string{"h2"},
-		},
+	serverOpts := conf.grpcOpts
+	var interceptor grpc.UnaryServerInterceptor
+	if len(conf.interceptors) > 0 {
+		interceptor = chainUnaryServer(conf.interceptors)
+		serverOpts = append(serverOpts, grpc.UnaryInterceptor(interceptor))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+	if conf.public {
+		drand.RegisterRandomnessServer(grpcServer, s)
+		drand.RegisterInfoServer(grpcServer, s)
+	}
+	if conf.private {
+		drand.RegisterBeaconServer(grpcServer, s)
+		dkg.RegisterDkgServer(grpcServer, s)
 	}
 
-	tlsListener := tls.NewListener(lis, server.TLSConfig)
-	g := &grpcTLSListener{
+	g := &grpcListener{
 		Service:    s,
-		server:     server,
 		grpcServer: grpcServer,
-		l:          tlsListener,
 	}
-	return g, nil
-}
 
-func (g *grpcTLSListener) Start() {
-	if err := g.server.Serve(g.l); err != nil {
-		slog.Debugf("grpc: tls listener start failed: %s", err)
+	var rest http.Handler = http.NotFoundHandler()
+	if !conf.restDisabled {
+		restHandler, err := newRESTHandler(s, conf.latestMaxAge)
+		if err != nil {
+			return nil, err
+		}
+		if conf.limiter != nil {
+			restHandler = conf.limiter.httpHandler(restHandler)
+		}
+		rest = restHandler
 	}
-}
 
-func (g *grpcTLSListener) Stop() {
-	// Graceful stop not supported with HTTP Server
-	// https://github.com/grpc/grpc-go/issues/1384
-	if err := g.server.Shutdown(context.TODO()); err != nil {
-		slog.Debugf("grpc: tls listener shutdown failed: %s", err)
+	if conf.private {
+		// the internal methods are also served over HTTP/1.1 for the nodes
+		// using the HTTP transport
+		mux := http.NewServeMux()
+		mux.Handle(internalPrefix+"/", newInternalHandler(s, interceptor))
+		mux.Handle("/", rest)
+		rest = mux
 	}
-}
 
-type drandProxy struct {
-	r drand.RandomnessServer
-	d drand.InfoServer
+	// gRPC requests on connections started by other requests are still
+	// served, without the keepalive settings of the gRPC server
+	handler := h2c.NewHandler(grpcHandlerFunc(grpcServer, rest), &http2.Server{})
+	g.server = &http.Server{Handler: handler}
+	l, err := listen(addr, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	// gRPC clients wait for the settings of the server before sending their
+	// first request
+	g.mux = cmux.New(l)
+	g.grpcL = g.mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	g.httpL = g.mux.Match(cmux.Any())
+	return g, nil
 }
 
-func (d *drandProxy) Public(c context.Context, r *drand.PublicRandRequest, opts ...grpc.CallOption) (*drand.PublicRandResponse, error) {
-	return d.r.Public(c, r)
+// listen binds to the given address, using TLS if the config is not nil.
+func listen(addr string, tlsConfig *tls.Config) (net.Listener, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		return tls.NewListener(l, tlsConfig), nil
+	}
+	return l, nil
 }
-func (d *drandProxy) Private(c context.Context, r *drand.PrivateRandRequest, opts ...grpc.CallOption) (*drand.PrivateRandResponse, error) {
-	return d.r.Private(c, r)
+
+// newRESTHandler returns the handler of the REST API, which forwards the
+// requests to the given service. latestMaxAge, if not nil, returns how long the
+// latest beacon can be cached.
+func newRESTHandler(s Service, latestMaxAge func() time.Duration) (http.Handler, error) {
+	gwMux := runtime.NewServeMux(restMarshalers()...)
+	proxy := newProxyClient(s)
+	ctx := context.Background()
+	if err := drand.RegisterRandomnessHandlerClient(ctx, gwMux, proxy); err != nil {
+		return nil, err
+	}
+	if err := drand.RegisterInfoHandlerClient(ctx, gwMux, proxy); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	cached := cachingHandler(gwMux, latestMaxAge)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
+		cached.ServeHTTP(w, r)
+	}))
+	return mux, nil
 }
 
-func (d *drandProxy) DistKey(c context.Context, r *drand.DistKeyRequest, opts ...grpc.CallOption) (*drand.DistKeyResponse, error) {
-	return d.d.DistKey(c, r)
+func (g *grpcListener) Start() {
+	go g.grpcServer.Serve(g.grpcL)
+	go g.server.Serve(g.httpL)
+	if err := g.mux.Serve(); err != nil && !strings.Contains(err.Error(), "use of closed network connection") {
+		slog.Debugf("grpc: listener start failed: %s", err)
+	}
 }
-func (d *drandProxy) Home(c context.Context, r *drand.HomeRequest, opts ...grpc.CallOption) (*drand.HomeResponse, error) {
-	return d.d.Home(c, r)
+
+// Stop closes the listeners and waits for the requests in progress, for at
+// most ShutdownTimeout, before closing the remaining connections.
+func (g *grpcListener) Stop() {
+	// Graceful stop not supported with HTTP Server
+	// https://github.com/grpc/grpc-go/issues/1384
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	// closing the HTTP listener closes the listener shared by both servers
+	if err := g.server.Shutdown(ctx); err != nil {
+		slog.Debugf("grpc: listener shutdown failed: %s", err)
+	}
+	g.grpcServer.Stop()
 }
 
 // grpcHandlerFunc returns an http.Handler that delegates to grpcServer on
@@ -213,8 +268,6 @@ func (d *drandProxy) Home(c context.Context, r *drand.HomeRequest, opts ...grpc.
 // taken from https://github.com/philips/grpc-gateway-example
 func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 		// TODO(tamird): point to merged gRPC code rather than a PR.
 		// This is a partial recreation of gRPC's internal checks https://github.com/grpc/grpc-go/pull/514/files#diff-95e9a25b738459a2d3030e1e6fa2a718R61
 		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
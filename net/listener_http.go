@@ -0,0 +1,119 @@
+package net
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dedis/drand/protobuf/dkg"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// internalMethod decodes the request of an internal method and calls the
+// service with it.
+type internalMethod struct {
+	request func() proto.Message
+	call    func(ctx context.Context, s Service, req proto.Message) (proto.Message, error)
+}
+
+var internalMethods = map[string]internalMethod{
+	"/drand.Beacon/NewBeacon": {
+		request: func() proto.Message { return new(drand.BeaconRequest) },
+		call: func(ctx context.Context, s Service, req proto.Message) (proto.Message, error) {
+			return s.NewBeacon(ctx, req.(*drand.BeaconRequest))
+		},
+	},
+	"/dkg.Dkg/Setup": {
+		request: func() proto.Message { return new(dkg.DKGPacket) },
+		call: func(ctx context.Context, s Service, req proto.Message) (proto.Message, error) {
+			return s.Setup(ctx, req.(*dkg.DKGPacket))
+		},
+	},
+	"/dkg.Dkg/Reshare": {
+		request: func() proto.Message { return new(dkg.ResharePacket) },
+		call: func(ctx context.Context, s Service, req proto.Message) (proto.Message, error) {
+			return s.Reshare(ctx, req.(*dkg.ResharePacket))
+		},
+	},
+}
+
+// newInternalHandler returns the handler serving the internal methods over
+// the HTTP transport. The authentication headers are passed to the
+// interceptor, if not nil, as the metadata of a gRPC request would be.
+func newInternalHandler(s Service, interceptor grpc.UnaryServerInterceptor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, internalPrefix)
+		m, ok := internalMethods[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != "POST" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxInternalSize))
+		if err != nil {
+			writeInternalError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		req := m.request()
+		if err := proto.Unmarshal(body, req); err != nil {
+			writeInternalError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		md := metadata.MD{}
+		for _, k := range []string{authAddrKey, authTimeKey} {
+			if v := r.Header.Get(k); v != "" {
+				md.Set(k, v)
+			}
+		}
+		if v := r.Header.Get(authSigKey); v != "" {
+			sig, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				writeInternalError(w, status.Error(codes.Unauthenticated, "drand: invalid authentication signature"))
+				return
+			}
+			md.Set(authSigKey, string(sig))
+		}
+		ctx := metadata.NewIncomingContext(r.Context(), md)
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return m.call(ctx, s, req.(proto.Message))
+		}
+		var resp interface{}
+		if interceptor != nil {
+			info := &grpc.UnaryServerInfo{Server: s, FullMethod: name}
+			resp, err = interceptor(ctx, req, info, handler)
+		} else {
+			resp, err = handler(ctx, req)
+		}
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		buff, err := proto.Marshal(resp.(proto.Message))
+		if err != nil {
+			writeInternalError(w, status.Error(codes.Internal, err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", MIMEProtobuf)
+		w.Write(buff)
+	})
+}
+
+// writeInternalError writes the error with its gRPC code in statusHeader.
+func writeInternalError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	w.Header().Set(statusHeader, strconv.Itoa(int(st.Code())))
+	http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+}
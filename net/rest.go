@@ -0,0 +1,277 @@
+package net
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// Media types of the REST API. Clients choose one with the Accept header, the
+// default being the hex encoded JSON.
+const (
+	// MIMEHexJSON is JSON with byte fields encoded in hexadecimal.
+	MIMEHexJSON = "application/json"
+	// MIMEBase64JSON is JSON with byte fields encoded in base64, following the
+	// protobuf JSON mapping.
+	MIMEBase64JSON = "application/vnd.drand.base64+json"
+	// MIMEProtobuf is the protobuf encoding of the responses.
+	MIMEProtobuf = "application/x-protobuf"
+	// MIMEBinary is the raw value of the response: the signature of a beacon or
+	// the distributed key. Other responses are encoded as protobuf.
+	MIMEBinary = "application/octet-stream"
+)
+
+// immutableCache is the Cache-Control value of the beacons of past rounds,
+// which never change.
+const immutableCache = "public, max-age=31536000, immutable"
+
+// restMarshalers returns the options registering the marshalers of all the
+// media types of the REST API.
+func restMarshalers() []runtime.ServeMuxOption {
+	base64JSON := &runtime.JSONPb{OrigName: true}
+	return []runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, defaultJSONMarshaller),
+		runtime.WithMarshalerOption(MIMEHexJSON, defaultJSONMarshaller),
+		runtime.WithMarshalerOption(MIMEBase64JSON, &contentType{base64JSON, MIMEBase64JSON}),
+		runtime.WithMarshalerOption(MIMEProtobuf, &protoMarshaler{MIMEProtobuf}),
+		runtime.WithMarshalerOption(MIMEBinary, &binaryMarshaler{protoMarshaler{MIMEBinary}}),
+	}
+}
+
+// negotiate returns the supported media type preferred by the given Accept
+// header, or an empty string if none is acceptable, in which case the default
+// one is used.
+func negotiate(accept string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case "*/*", "application/*":
+			mediaType = MIMEHexJSON
+		case "application/protobuf":
+			mediaType = MIMEProtobuf
+		case MIMEHexJSON, MIMEBase64JSON, MIMEProtobuf, MIMEBinary:
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = mediaType, q
+		}
+	}
+	return best
+}
+
+// isRound returns true if the value of a round query parameter designates a
+// given round rather than the latest one.
+func isRound(v string) bool {
+	round, err := strconv.ParseUint(v, 10, 64)
+	return err == nil && round != 0
+}
+
+// cachingHandler negotiates the media type of the responses and sets their
+// caching headers: the beacons requested by round are immutable, the latest
+// beacon and the beacons requested by time can be cached until the next round,
+// given by latestMaxAge if not nil, and other responses must be revalidated.
+// Successful GET responses carry an ETag and conditional requests are answered
+// with 304 Not Modified.
+func cachingHandler(h http.Handler, latestMaxAge func() time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mediaType := negotiate(r.Header.Get("Accept")); mediaType != "" {
+			r.Header.Set("Accept", mediaType)
+		} else {
+			r.Header.Del("Accept")
+		}
+		w.Header().Add("Vary", "Accept")
+		if r.Method != http.MethodGet {
+			w.Header().Set("Cache-Control", "no-store")
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{header: w.Header(), status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if rec.status != http.StatusOK {
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+
+		// the round is given in the path or as a parameter, round 0 being the
+		// latest one
+		public := r.URL.Path == "/api/public" || strings.HasPrefix(r.URL.Path, "/api/public/")
+		round := r.URL.Query().Get("round")
+		if strings.HasPrefix(r.URL.Path, "/api/public/") {
+			round = strings.TrimPrefix(r.URL.Path, "/api/public/")
+		}
+		switch {
+		case public && isRound(round):
+			w.Header().Set("Cache-Control", immutableCache)
+		case public && latestMaxAge != nil:
+			maxAge := int64(latestMaxAge() / time.Second)
+			if maxAge < 0 {
+				maxAge = 0
+			}
+			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+		default:
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		hash := sha256.Sum256(rec.body.Bytes())
+		etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		if matchETag(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(rec.body.Bytes())
+	})
+}
+
+// matchETag returns true if the If-None-Match header matches the ETag.
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, t := range strings.Split(ifNoneMatch, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == etag || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRecorder buffers a response so its headers can be completed once
+// the body is known.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// contentType overrides the media type of a marshaler.
+type contentType struct {
+	runtime.Marshaler
+	mediaType string
+}
+
+func (c *contentType) ContentType() string {
+	return c.mediaType
+}
+
+// protoMarshaler encodes protobuf messages in their binary form.
+type protoMarshaler struct {
+	mediaType string
+}
+
+func (p *protoMarshaler) ContentType() string {
+	return p.mediaType
+}
+
+func (p *protoMarshaler) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("net: %T is not a protobuf message", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (p *protoMarshaler) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("net: %T is not a protobuf message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+func (p *protoMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return decoderFunc(func(v interface{}) error {
+		buff, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return p.Unmarshal(buff, v)
+	})
+}
+
+func (p *protoMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return encoderFunc(func(v interface{}) error {
+		buff, err := p.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buff)
+		return err
+	})
+}
+
+// binaryMarshaler writes the raw bytes of the signature of a beacon or of the
+// distributed key, and the protobuf encoding of other messages.
+type binaryMarshaler struct {
+	protoMarshaler
+}
+
+func (b *binaryMarshaler) Marshal(v interface{}) ([]byte, error) {
+	switch resp := v.(type) {
+	case *drand.PublicRandResponse:
+		return resp.GetRandomness().GetPoint(), nil
+	case *drand.DistKeyResponse:
+		return resp.GetKey().GetPoint(), nil
+	}
+	return b.protoMarshaler.Marshal(v)
+}
+
+func (b *binaryMarshaler) Unmarshal(data []byte, v interface{}) error {
+	return errors.New("net: binary requests are not supported")
+}
+
+func (b *binaryMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return encoderFunc(func(v interface{}) error {
+		buff, err := b.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buff)
+		return err
+	})
+}
+
+type decoderFunc func(v interface{}) error
+
+func (f decoderFunc) Decode(v interface{}) error { return f(v) }
+
+type encoderFunc func(v interface{}) error
+
+func (f encoderFunc) Encode(v interface{}) error { return f(v) }
@@ -0,0 +1,99 @@
+package net
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dedis/drand/protobuf/crypto"
+	"github.com/dedis/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTNegotiate(t *testing.T) {
+	require.Equal(t, "", negotiate(""))
+	require.Equal(t, "", negotiate("text/html"))
+	require.Equal(t, MIMEHexJSON, negotiate("*/*"))
+	require.Equal(t, MIMEProtobuf, negotiate("application/protobuf"))
+	require.Equal(t, MIMEBinary, negotiate("application/json;q=0.5, application/octet-stream"))
+	require.Equal(t, MIMEBase64JSON, negotiate("text/html, "+MIMEBase64JSON+";q=0.9, */*;q=0.1"))
+}
+
+func TestRESTCaching(t *testing.T) {
+	var accept string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		if r.URL.Path == "/api/public/1000" {
+			http.Error(w, "not found", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("beacon"))
+	})
+	h := cachingHandler(handler, func() time.Duration { return 12500 * time.Millisecond })
+
+	get := func(path string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/api/public/12", "Accept", "application/protobuf")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, MIMEProtobuf, accept)
+	require.Equal(t, immutableCache, rec.Header().Get("Cache-Control"))
+	require.Equal(t, "beacon", rec.Body.String())
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec = get("/api/public/12", "If-None-Match", etag)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.String())
+
+	rec = get("/api/public")
+	require.Equal(t, "public, max-age=12", rec.Header().Get("Cache-Control"))
+	require.Equal(t, "", accept)
+
+	rec = get("/api/public?round=12")
+	require.Equal(t, immutableCache, rec.Header().Get("Cache-Control"))
+
+	// round 0 is the latest beacon
+	rec = get("/api/public/0")
+	require.Equal(t, "public, max-age=12", rec.Header().Get("Cache-Control"))
+	rec = get("/api/public?round=0")
+	require.Equal(t, "public, max-age=12", rec.Header().Get("Cache-Control"))
+
+	// the round due at a time may not be the latest one yet
+	rec = get("/api/public?time=1559390400")
+	require.Equal(t, "public, max-age=12", rec.Header().Get("Cache-Control"))
+
+	rec = get("/api/info/distkey")
+	require.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	// rounds not produced yet must not be cached
+	rec = get("/api/public/1000")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	require.Empty(t, rec.Header().Get("ETag"))
+}
+
+func TestRESTBinaryMarshaler(t *testing.T) {
+	m := &binaryMarshaler{protoMarshaler{MIMEBinary}}
+	resp := &drand.PublicRandResponse{
+		Round:      12,
+		Randomness: &crypto.Point{Point: []byte{1, 2, 3}},
+	}
+	buff, err := m.Marshal(resp)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, buff)
+
+	buff, err = m.Marshal(&drand.HomeResponse{Status: "ok"})
+	require.NoError(t, err)
+	var home drand.HomeResponse
+	require.NoError(t, m.protoMarshaler.Unmarshal(buff, &home))
+	require.Equal(t, "ok", home.GetStatus())
+}
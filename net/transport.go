@@ -0,0 +1,94 @@
+package net
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dedis/drand/protobuf/dkg"
+	"github.com/dedis/drand/protobuf/drand"
+)
+
+// Transports over which nodes accept the requests of other nodes. A node
+// declares its transport in the group file.
+const (
+	// TransportGRPC is the default transport, gRPC over HTTP/2.
+	TransportGRPC = "grpc"
+	// TransportHTTP sends each request as a POST of its protobuf encoding over
+	// HTTP/1.1, for nodes behind proxies that can not carry gRPC traffic.
+	TransportHTTP = "http"
+)
+
+// TransportPeer is a Peer declaring the transport it accepts requests over.
+// Peers that do not implement it, or return an empty string, are contacted
+// over gRPC.
+type TransportPeer interface {
+	Peer
+	PeerTransport() string
+}
+
+// transportOf returns the transport of the peer.
+func transportOf(p Peer) string {
+	if tp, ok := p.(TransportPeer); ok && tp.PeerTransport() != "" {
+		return tp.PeerTransport()
+	}
+	return TransportGRPC
+}
+
+// transportClient dispatches the requests to the client of the transport of
+// each peer.
+type transportClient struct {
+	clients map[string]InternalClient
+}
+
+// NewTransportClient returns an InternalClient sending each request with the
+// client, among the given ones indexed by transport name, of the transport
+// declared by the peer.
+func NewTransportClient(clients map[string]InternalClient) InternalClient {
+	return &transportClient{clients: clients}
+}
+
+func (t *transportClient) client(p Peer) (InternalClient, error) {
+	c, ok := t.clients[transportOf(p)]
+	if !ok {
+		return nil, fmt.Errorf("net: unsupported transport %q for %s", transportOf(p), p.Address())
+	}
+	return c, nil
+}
+
+func (t *transportClient) NewBeacon(p Peer, in *drand.BeaconRequest, opts ...CallOption) (*drand.BeaconResponse, error) {
+	c, err := t.client(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewBeacon(p, in, opts...)
+}
+
+func (t *transportClient) Setup(p Peer, in *dkg.DKGPacket, opts ...CallOption) (*dkg.DKGResponse, error) {
+	c, err := t.client(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.Setup(p, in, opts...)
+}
+
+func (t *transportClient) Reshare(p Peer, in *dkg.ResharePacket, opts ...CallOption) (*dkg.ReshareResponse, error) {
+	c, err := t.client(p)
+	if err != nil {
+		return nil, err
+	}
+	return c.Reshare(p, in, opts...)
+}
+
+func (t *transportClient) SetTimeout(d time.Duration) {
+	for _, c := range t.clients {
+		c.SetTimeout(d)
+	}
+}
+
+func (t *transportClient) Connections() []ConnStatus {
+	var list []ConnStatus
+	for _, c := range t.clients {
+		list = append(list, c.Connections()...)
+	}
+	return list
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dedis/drand/key"
+	json "github.com/nikkolasg/hexjson"
+	"github.com/nikkolasg/slog"
+	"github.com/urfave/cli"
+)
+
+// Commands print their result, the data, on stdout in the format given by
+// --format, and nothing else: the banner, the progress messages, the
+// warnings and the errors go to stderr, so the output of a command can be
+// parsed. They exit with 0 when they succeeded, 1 when they failed and
+// exitUsage when the arguments or flags of the command are invalid.
+const exitUsage = 2
+
+// Formats of the data printed on stdout.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatTOML = "toml"
+)
+
+var formatFlag = cli.StringFlag{
+	Name:  "format",
+	Value: formatText,
+	Usage: "Format of the data printed on stdout: \"json\", \"toml\" or \"text\" for the human readable one.",
+}
+
+// printData writes the data v to w, the stdout of the command, in the format
+// asked by the user. Key material and groups are printed as they are saved on
+// disk in the text format, and other values as one "name: value" line per
+// field.
+func printData(w io.Writer, c *cli.Context, v interface{}) {
+	format := c.String(formatFlag.Name)
+	t, isTomler := v.(key.Tomler)
+	if isTomler {
+		v = t.TOML()
+		if format == formatText {
+			format = formatTOML
+		}
+	}
+	var buff []byte
+	var err error
+	switch format {
+	case formatText:
+		buff, err = textData(v)
+	case formatJSON:
+		buff, err = json.MarshalIndent(v, "", "    ")
+		buff = append(buff, '\n')
+	case formatTOML:
+		buff, err = tomlData(v, isTomler)
+	default:
+		fatalUsage("unknown format %q, expected json, toml or text", format)
+	}
+	if err != nil {
+		slog.Fatalf("drand: could not encode the output: %s", err)
+	}
+	w.Write(buff)
+}
+
+// textData encodes v as one "name: value" line per field, sorted by name.
+// The fields of nested values are named after their parents, separated by
+// dots, and bytes are written as hexadecimal strings.
+func textData(v interface{}) ([]byte, error) {
+	m, err := jsonFields(v)
+	if err != nil {
+		return nil, err
+	}
+	var buff bytes.Buffer
+	var write func(prefix string, v interface{})
+	write = func(prefix string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			names := make([]string, 0, len(v))
+			for name := range v {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				write(prefix+name+".", v[name])
+			}
+		case []interface{}:
+			for i, e := range v {
+				write(prefix+strconv.Itoa(i)+".", e)
+			}
+		default:
+			fmt.Fprintf(&buff, "%s: %v\n", strings.TrimSuffix(prefix, "."), v)
+		}
+	}
+	write("", m)
+	return buff.Bytes(), nil
+}
+
+// jsonFields decodes the JSON representation of v, with its numbers as
+// integers or floats.
+func jsonFields(v interface{}) (map[string]interface{}, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return tomlNumbers(m).(map[string]interface{}), nil
+}
+
+// tomlData encodes v in TOML. Values which are not TOML-compatible, such as
+// the protobuf messages, are encoded from their JSON representation so bytes
+// are written as hexadecimal strings.
+func tomlData(v interface{}, compatible bool) ([]byte, error) {
+	if !compatible {
+		m, err := jsonFields(v)
+		if err != nil {
+			return nil, err
+		}
+		v = m
+	}
+	var buff bytes.Buffer
+	err := toml.NewEncoder(&buff).Encode(v)
+	return buff.Bytes(), err
+}
+
+// tomlNumbers replaces the JSON numbers of the decoded value by integers, or
+// floats when they are not, so they are encoded as such in TOML.
+func tomlNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = tomlNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = tomlNumbers(e)
+		}
+	}
+	return v
+}
+
+// printInfo prints a message for the user on stderr, as stdout is kept for the
+// data.
+func printInfo(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+// fatalUsage prints the error on stderr and exits with exitUsage. It is
+// called when the arguments or flags of a command are invalid.
+func fatalUsage(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "drand: "+format+"\n", args...)
+	os.Exit(exitUsage)
+}
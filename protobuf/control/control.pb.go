@@ -694,6 +694,9 @@ func (m *CokeyResponse) GetCoKey() *crypto.Point {
 }
 
 type GroupRequest struct {
+	// epoch requests the group of the given epoch of the history of the
+	// node, numbered from 1, instead of the current group when it is 0.
+	Epoch                uint32   `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -723,6 +726,13 @@ func (m *GroupRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GroupRequest proto.InternalMessageInfo
 
+func (m *GroupRequest) GetEpoch() uint32 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
 type GroupResponse struct {
 	// TOML-encoded group file
 	GroupToml            string   `protobuf:"bytes,1,opt,name=groupToml,proto3" json:"groupToml,omitempty"`
@@ -762,6 +772,295 @@ func (m *GroupResponse) GetGroupToml() string {
 	return ""
 }
 
+type ConnectionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionsRequest) Reset()         { *m = ConnectionsRequest{} }
+func (m *ConnectionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ConnectionsRequest) ProtoMessage()    {}
+func (*ConnectionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{17}
+}
+func (m *ConnectionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionsRequest.Unmarshal(m, b)
+}
+func (m *ConnectionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ConnectionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionsRequest.Merge(dst, src)
+}
+func (m *ConnectionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ConnectionsRequest.Size(m)
+}
+func (m *ConnectionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionsRequest proto.InternalMessageInfo
+
+// ConnectionStatus describes the connection of a node to another node
+type ConnectionStatus struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Tls     bool   `protobuf:"varint,2,opt,name=tls,proto3" json:"tls,omitempty"`
+	// state of the gRPC connection: IDLE, CONNECTING, READY,
+	// TRANSIENT_FAILURE, SHUTDOWN, or CLOSED once evicted
+	State string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// number of consecutive failed calls
+	Failures  uint32 `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`
+	LastError string `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// number of times the connection was evicted and dialed again
+	Reconnects           uint32   `protobuf:"varint,6,opt,name=reconnects,proto3" json:"reconnects,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionStatus) Reset()         { *m = ConnectionStatus{} }
+func (m *ConnectionStatus) String() string { return proto.CompactTextString(m) }
+func (*ConnectionStatus) ProtoMessage()    {}
+func (*ConnectionStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{18}
+}
+func (m *ConnectionStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionStatus.Unmarshal(m, b)
+}
+func (m *ConnectionStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionStatus.Marshal(b, m, deterministic)
+}
+func (dst *ConnectionStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionStatus.Merge(dst, src)
+}
+func (m *ConnectionStatus) XXX_Size() int {
+	return xxx_messageInfo_ConnectionStatus.Size(m)
+}
+func (m *ConnectionStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionStatus proto.InternalMessageInfo
+
+func (m *ConnectionStatus) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ConnectionStatus) GetTls() bool {
+	if m != nil {
+		return m.Tls
+	}
+	return false
+}
+
+func (m *ConnectionStatus) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ConnectionStatus) GetFailures() uint32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *ConnectionStatus) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *ConnectionStatus) GetReconnects() uint32 {
+	if m != nil {
+		return m.Reconnects
+	}
+	return 0
+}
+
+type ConnectionsResponse struct {
+	Connections          []*ConnectionStatus `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ConnectionsResponse) Reset()         { *m = ConnectionsResponse{} }
+func (m *ConnectionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ConnectionsResponse) ProtoMessage()    {}
+func (*ConnectionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{19}
+}
+func (m *ConnectionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionsResponse.Unmarshal(m, b)
+}
+func (m *ConnectionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ConnectionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionsResponse.Merge(dst, src)
+}
+func (m *ConnectionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ConnectionsResponse.Size(m)
+}
+func (m *ConnectionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionsResponse proto.InternalMessageInfo
+
+func (m *ConnectionsResponse) GetConnections() []*ConnectionStatus {
+	if m != nil {
+		return m.Connections
+	}
+	return nil
+}
+
+type ShutdownRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
+func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
+func (*ShutdownRequest) ProtoMessage()    {}
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{20}
+}
+func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownRequest.Unmarshal(m, b)
+}
+func (m *ShutdownRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownRequest.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownRequest.Merge(dst, src)
+}
+func (m *ShutdownRequest) XXX_Size() int {
+	return xxx_messageInfo_ShutdownRequest.Size(m)
+}
+func (m *ShutdownRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownRequest proto.InternalMessageInfo
+
+type ShutdownResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShutdownResponse) Reset()         { *m = ShutdownResponse{} }
+func (m *ShutdownResponse) String() string { return proto.CompactTextString(m) }
+func (*ShutdownResponse) ProtoMessage()    {}
+func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{21}
+}
+func (m *ShutdownResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShutdownResponse.Unmarshal(m, b)
+}
+func (m *ShutdownResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShutdownResponse.Marshal(b, m, deterministic)
+}
+func (dst *ShutdownResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShutdownResponse.Merge(dst, src)
+}
+func (m *ShutdownResponse) XXX_Size() int {
+	return xxx_messageInfo_ShutdownResponse.Size(m)
+}
+func (m *ShutdownResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShutdownResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShutdownResponse proto.InternalMessageInfo
+
+type RotateKeyRequest struct {
+	// new group, holding the next key pair of the node at its address
+	New *GroupInfo `protobuf:"bytes,1,opt,name=new,proto3" json:"new,omitempty"`
+	// timeout as parsed by Golang's time.ParseDuration method.
+	Timeout              string   `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RotateKeyRequest) Reset()         { *m = RotateKeyRequest{} }
+func (m *RotateKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RotateKeyRequest) ProtoMessage()    {}
+func (*RotateKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{22}
+}
+func (m *RotateKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateKeyRequest.Unmarshal(m, b)
+}
+func (m *RotateKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateKeyRequest.Marshal(b, m, deterministic)
+}
+func (dst *RotateKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateKeyRequest.Merge(dst, src)
+}
+func (m *RotateKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RotateKeyRequest.Size(m)
+}
+func (m *RotateKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateKeyRequest proto.InternalMessageInfo
+
+func (m *RotateKeyRequest) GetNew() *GroupInfo {
+	if m != nil {
+		return m.New
+	}
+	return nil
+}
+
+func (m *RotateKeyRequest) GetTimeout() string {
+	if m != nil {
+		return m.Timeout
+	}
+	return ""
+}
+
+type RotateKeyResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RotateKeyResponse) Reset()         { *m = RotateKeyResponse{} }
+func (m *RotateKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RotateKeyResponse) ProtoMessage()    {}
+func (*RotateKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_control_620edffbeedce32e, []int{23}
+}
+func (m *RotateKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateKeyResponse.Unmarshal(m, b)
+}
+func (m *RotateKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateKeyResponse.Marshal(b, m, deterministic)
+}
+func (dst *RotateKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateKeyResponse.Merge(dst, src)
+}
+func (m *RotateKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_RotateKeyResponse.Size(m)
+}
+func (m *RotateKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateKeyResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*DKGRequest)(nil), "control.DKGRequest")
 	proto.RegisterType((*DKGResponse)(nil), "control.DKGResponse")
@@ -780,6 +1079,13 @@ func init() {
 	proto.RegisterType((*CokeyResponse)(nil), "control.CokeyResponse")
 	proto.RegisterType((*GroupRequest)(nil), "control.GroupRequest")
 	proto.RegisterType((*GroupResponse)(nil), "control.GroupResponse")
+	proto.RegisterType((*ConnectionsRequest)(nil), "control.ConnectionsRequest")
+	proto.RegisterType((*ConnectionStatus)(nil), "control.ConnectionStatus")
+	proto.RegisterType((*ConnectionsResponse)(nil), "control.ConnectionsResponse")
+	proto.RegisterType((*ShutdownRequest)(nil), "control.ShutdownRequest")
+	proto.RegisterType((*ShutdownResponse)(nil), "control.ShutdownResponse")
+	proto.RegisterType((*RotateKeyRequest)(nil), "control.RotateKeyRequest")
+	proto.RegisterType((*RotateKeyResponse)(nil), "control.RotateKeyResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -811,6 +1117,15 @@ type ControlClient interface {
 	CollectiveKey(ctx context.Context, in *CokeyRequest, opts ...grpc.CallOption) (*CokeyResponse, error)
 	// Group returns the current group file used
 	Group(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	// Connections returns the state of the connections to the other nodes
+	Connections(ctx context.Context, in *ConnectionsRequest, opts ...grpc.CallOption) (*ConnectionsResponse, error)
+	// Shutdown stops the daemon gracefully: the current round is finished or
+	// abandoned, the connections are drained and the database is closed.
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	// RotateKey replaces the longterm key of the node with the next key
+	// pair saved in its key store, by running a resharing towards the given
+	// group in which the node holds the new key.
+	RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (*RotateKeyResponse, error)
 }
 
 type controlClient struct {
@@ -893,6 +1208,33 @@ func (c *controlClient) Group(ctx context.Context, in *GroupRequest, opts ...grp
 	return out, nil
 }
 
+func (c *controlClient) Connections(ctx context.Context, in *ConnectionsRequest, opts ...grpc.CallOption) (*ConnectionsResponse, error) {
+	out := new(ConnectionsResponse)
+	err := c.cc.Invoke(ctx, "/control.Control/Connections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
+	out := new(ShutdownResponse)
+	err := c.cc.Invoke(ctx, "/control.Control/Shutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) RotateKey(ctx context.Context, in *RotateKeyRequest, opts ...grpc.CallOption) (*RotateKeyResponse, error) {
+	out := new(RotateKeyResponse)
+	err := c.cc.Invoke(ctx, "/control.Control/RotateKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServer is the server API for Control service.
 type ControlServer interface {
 	// PingPong returns an empty message. Purpose is to test the control port.
@@ -912,6 +1254,15 @@ type ControlServer interface {
 	CollectiveKey(context.Context, *CokeyRequest) (*CokeyResponse, error)
 	// Group returns the current group file used
 	Group(context.Context, *GroupRequest) (*GroupResponse, error)
+	// Connections returns the state of the connections to the other nodes
+	Connections(context.Context, *ConnectionsRequest) (*ConnectionsResponse, error)
+	// Shutdown stops the daemon gracefully: the current round is finished or
+	// abandoned, the connections are drained and the database is closed.
+	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	// RotateKey replaces the longterm key of the node with the next key
+	// pair saved in its key store, by running a resharing towards the given
+	// group in which the node holds the new key.
+	RotateKey(context.Context, *RotateKeyRequest) (*RotateKeyResponse, error)
 }
 
 func RegisterControlServer(s *grpc.Server, srv ControlServer) {
@@ -1062,6 +1413,60 @@ func _Control_Group_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_Connections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Connections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/control.Control/Connections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Connections(ctx, req.(*ConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/control.Control/Shutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_RotateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).RotateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/control.Control/RotateKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).RotateKey(ctx, req.(*RotateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Control_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "control.Control",
 	HandlerType: (*ControlServer)(nil),
@@ -1098,6 +1503,18 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Group",
 			Handler:    _Control_Group_Handler,
 		},
+		{
+			MethodName: "Connections",
+			Handler:    _Control_Connections_Handler,
+		},
+		{
+			MethodName: "Shutdown",
+			Handler:    _Control_Shutdown_Handler,
+		},
+		{
+			MethodName: "RotateKey",
+			Handler:    _Control_RotateKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "control/control.proto",
@@ -1106,44 +1523,58 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("control/control.proto", fileDescriptor_control_620edffbeedce32e) }
 
 var fileDescriptor_control_620edffbeedce32e = []byte{
-	// 615 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x5f, 0x6f, 0xd3, 0x3e,
-	0x14, 0x6d, 0x7f, 0x5d, 0xff, 0xe4, 0xee, 0x97, 0x8d, 0x79, 0x1d, 0x94, 0x8c, 0x87, 0x29, 0x1a,
-	0x6c, 0x20, 0xd1, 0x48, 0x43, 0x20, 0x24, 0x84, 0x04, 0xdb, 0xa4, 0x32, 0x75, 0x0f, 0x55, 0xc6,
-	0x13, 0x2f, 0x53, 0x9a, 0x78, 0xa9, 0xd5, 0xd4, 0x0e, 0x89, 0xb3, 0xd1, 0xcf, 0xc1, 0x77, 0xe5,
-	0x19, 0xd9, 0x71, 0x9c, 0xa4, 0x5d, 0x79, 0x8a, 0xce, 0x3d, 0xd7, 0xe7, 0xfe, 0xf1, 0x89, 0xe1,
-	0xc0, 0x67, 0x94, 0x27, 0x2c, 0x72, 0xd4, 0x77, 0x18, 0x27, 0x8c, 0x33, 0xd4, 0x55, 0xd0, 0xea,
-	0xfb, 0xc9, 0x32, 0xe6, 0xcc, 0xc1, 0x11, 0x5e, 0x60, 0xca, 0x73, 0xda, 0xe6, 0x00, 0x97, 0xe3,
-	0x91, 0x8b, 0x7f, 0x66, 0x38, 0xe5, 0xc8, 0x01, 0x23, 0x98, 0x87, 0xb7, 0x61, 0xc2, 0xb2, 0x78,
-	0xd0, 0x3c, 0x6a, 0x9e, 0x6e, 0x9f, 0xa1, 0x61, 0xa1, 0x37, 0x12, 0xd1, 0x2b, 0x7a, 0xc7, 0xdc,
-	0x5e, 0x30, 0x0f, 0x25, 0x42, 0x87, 0x60, 0x90, 0xf4, 0x36, 0xc2, 0x5e, 0x80, 0x93, 0xc1, 0x7f,
-	0x47, 0xcd, 0xd3, 0x9e, 0xdb, 0x23, 0xe9, 0xb5, 0xc4, 0x68, 0x00, 0x5d, 0x4e, 0x16, 0x98, 0x65,
-	0x7c, 0xd0, 0x3a, 0x6a, 0x9e, 0x1a, 0x6e, 0x01, 0x6d, 0x13, 0xb6, 0x65, 0xd5, 0x34, 0x66, 0x34,
-	0xc5, 0xf6, 0xef, 0x26, 0xec, 0xb8, 0x38, 0x9d, 0x79, 0x09, 0x2e, 0x3a, 0x39, 0x86, 0x16, 0x8b,
-	0x82, 0x7f, 0xf4, 0x20, 0x68, 0x91, 0x45, 0xf1, 0x83, 0x2c, 0xbc, 0x21, 0x8b, 0xe2, 0x87, 0x7a,
-	0x93, 0xad, 0xcd, 0x4d, 0x6e, 0xd5, 0x9b, 0xfc, 0x0a, 0x86, 0x16, 0x42, 0x7d, 0xd8, 0x8a, 0x3d,
-	0x3e, 0x93, 0x0d, 0x19, 0xdf, 0x1a, 0xae, 0x44, 0x08, 0x41, 0x2b, 0x4b, 0x22, 0x59, 0x5f, 0x04,
-	0x05, 0x38, 0x07, 0xe8, 0x45, 0xcc, 0xf7, 0x38, 0x61, 0xd4, 0xde, 0x83, 0x5d, 0x3d, 0x97, 0x9a,
-	0x75, 0x07, 0xfe, 0xbf, 0xa9, 0x0c, 0x6a, 0x5f, 0x83, 0x79, 0x53, 0x4d, 0x40, 0x7d, 0x68, 0x13,
-	0x1a, 0xe0, 0x5f, 0xb2, 0x94, 0xe9, 0xe6, 0x00, 0xbd, 0x84, 0xb6, 0xd4, 0x51, 0xb3, 0xee, 0x0e,
-	0x8b, 0x6b, 0xbc, 0xf1, 0xbd, 0xc8, 0x4b, 0xdc, 0x9c, 0xb5, 0x3b, 0xb0, 0x35, 0x21, 0x34, 0x94,
-	0x5f, 0x46, 0x43, 0x1b, 0xc1, 0x93, 0x49, 0x36, 0x8d, 0x88, 0x3f, 0xc6, 0xcb, 0xa2, 0xe2, 0x27,
-	0xd8, 0xab, 0xc4, 0x54, 0xd5, 0x57, 0xd0, 0x89, 0xb3, 0xe9, 0x18, 0x2f, 0xd5, 0xca, 0x77, 0x74,
-	0x81, 0x09, 0x23, 0x94, 0xbb, 0x8a, 0xb5, 0xf7, 0x61, 0x6f, 0x92, 0x90, 0x7b, 0x8f, 0xe3, 0x8a,
-	0xe2, 0x67, 0x40, 0xd5, 0xa0, 0x92, 0x3c, 0x81, 0x4e, 0x9c, 0x90, 0x52, 0x72, 0xad, 0x67, 0x45,
-	0x8b, 0x95, 0x5c, 0xb0, 0x79, 0x29, 0xf7, 0x1e, 0x4c, 0x85, 0x95, 0xd2, 0x31, 0xb4, 0x7d, 0xb6,
-	0xb9, 0xb7, 0x9c, 0x14, 0x32, 0xf2, 0xbe, 0x0a, 0x99, 0xb7, 0x60, 0x2a, 0xac, 0x64, 0x5e, 0x80,
-	0x21, 0x9d, 0xfd, 0x9d, 0x2d, 0xa2, 0xfc, 0x22, 0xdd, 0x32, 0x70, 0xf6, 0xa7, 0x05, 0xdd, 0x8b,
-	0xdc, 0x40, 0xe8, 0x0d, 0xf4, 0xc4, 0x1a, 0xc5, 0x0a, 0x91, 0xa9, 0x6d, 0x25, 0x42, 0x56, 0x05,
-	0x8a, 0x05, 0x37, 0xd0, 0x07, 0xe8, 0x5e, 0x51, 0xc2, 0x2f, 0xc7, 0x23, 0xb4, 0xaf, 0xb9, 0xf2,
-	0x9f, 0xb2, 0xfa, 0xf5, 0xa0, 0xb2, 0x41, 0x03, 0x9d, 0xc3, 0xb6, 0x38, 0xa7, 0xfc, 0x81, 0x9e,
-	0xe9, 0xb4, 0xfa, 0x9f, 0x60, 0x0d, 0xd6, 0x09, 0xad, 0xf1, 0x11, 0xda, 0xd2, 0x3c, 0xe8, 0x40,
-	0x27, 0x55, 0xcd, 0x65, 0x3d, 0x5d, 0x0d, 0xeb, 0x93, 0x97, 0x60, 0x68, 0x13, 0xa0, 0xe7, 0xe5,
-	0x4c, 0x2b, 0x66, 0xb1, 0xac, 0xc7, 0x28, 0xad, 0x32, 0x02, 0x28, 0x2f, 0x1e, 0x55, 0x72, 0x57,
-	0x2d, 0x62, 0x1d, 0x3e, 0xca, 0x69, 0xa1, 0x2f, 0xe2, 0xca, 0xa3, 0x08, 0xfb, 0x9c, 0xdc, 0x4b,
-	0xad, 0x72, 0xa0, 0xaa, 0x35, 0x2a, 0x03, 0xd5, 0x1c, 0x92, 0xaf, 0x22, 0x7f, 0x92, 0x0e, 0xea,
-	0xcf, 0xc0, 0xfa, 0xc9, 0x9a, 0x29, 0xec, 0xc6, 0xf9, 0xeb, 0x1f, 0x27, 0x21, 0xe1, 0xb3, 0x6c,
-	0x3a, 0xf4, 0xd9, 0xc2, 0x09, 0x70, 0x40, 0x52, 0x27, 0x48, 0x3c, 0x1a, 0x38, 0xf2, 0x89, 0x9c,
-	0x66, 0x77, 0xc5, 0x93, 0x3a, 0xed, 0xc8, 0xc8, 0xbb, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0d,
-	0x99, 0x5e, 0x4e, 0x6c, 0x05, 0x00, 0x00,
+	// 837 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xd1, 0x6e, 0xdb, 0x36,
+	0x14, 0x8d, 0xe2, 0xd8, 0xb1, 0xaf, 0xeb, 0xd4, 0x61, 0x9c, 0x4e, 0x55, 0xba, 0xc1, 0x10, 0xb2,
+	0xd5, 0x1b, 0x30, 0x1b, 0xc8, 0xb0, 0x61, 0x40, 0x31, 0x60, 0x4d, 0x32, 0x78, 0x9d, 0xfb, 0x60,
+	0xc8, 0x7b, 0xda, 0x4b, 0x20, 0x4b, 0x8c, 0x2d, 0x44, 0x26, 0x35, 0x92, 0x6a, 0x96, 0xef, 0xd8,
+	0x87, 0xec, 0x43, 0xf6, 0x53, 0x05, 0x29, 0x8a, 0xa2, 0x1c, 0x27, 0x4f, 0xf6, 0x3d, 0xf7, 0xea,
+	0xf0, 0x5e, 0xde, 0x73, 0x24, 0x38, 0x8d, 0x28, 0x11, 0x8c, 0xa6, 0x13, 0xfd, 0x3b, 0xce, 0x18,
+	0x15, 0x14, 0x1d, 0xea, 0xd0, 0x1b, 0x44, 0xec, 0x21, 0x13, 0x74, 0x82, 0x53, 0xbc, 0xc1, 0x44,
+	0x14, 0x69, 0x5f, 0x00, 0x5c, 0xcf, 0xa6, 0x01, 0xfe, 0x3b, 0xc7, 0x5c, 0xa0, 0x09, 0x74, 0xe2,
+	0xbb, 0xd5, 0xcd, 0x8a, 0xd1, 0x3c, 0x73, 0x9d, 0xa1, 0x33, 0xea, 0x5e, 0xa0, 0x71, 0xc9, 0x37,
+	0x95, 0xe8, 0x07, 0x72, 0x4b, 0x83, 0x76, 0x7c, 0xb7, 0x52, 0x11, 0x3a, 0x83, 0x4e, 0xc2, 0x6f,
+	0x52, 0x1c, 0xc6, 0x98, 0xb9, 0xfb, 0x43, 0x67, 0xd4, 0x0e, 0xda, 0x09, 0xff, 0xa8, 0x62, 0xe4,
+	0xc2, 0xa1, 0x48, 0x36, 0x98, 0xe6, 0xc2, 0x6d, 0x0c, 0x9d, 0x51, 0x27, 0x28, 0x43, 0xbf, 0x07,
+	0x5d, 0x75, 0x2a, 0xcf, 0x28, 0xe1, 0xd8, 0xff, 0xd7, 0x81, 0xa3, 0x00, 0xf3, 0x75, 0xc8, 0x70,
+	0xd9, 0xc9, 0x39, 0x34, 0x68, 0x1a, 0x3f, 0xd3, 0x83, 0x4c, 0xcb, 0x2a, 0x82, 0xef, 0xdd, 0xfd,
+	0xa7, 0xab, 0x08, 0xbe, 0xaf, 0x37, 0xd9, 0x78, 0xba, 0xc9, 0x83, 0x7a, 0x93, 0xef, 0xa1, 0x63,
+	0x88, 0xd0, 0x00, 0x0e, 0xb2, 0x50, 0xac, 0x55, 0x43, 0x9d, 0xdf, 0xf7, 0x02, 0x15, 0x21, 0x04,
+	0x8d, 0x9c, 0xa5, 0xee, 0xbe, 0x06, 0x65, 0x70, 0x09, 0xd0, 0x4e, 0x69, 0x14, 0x8a, 0x84, 0x12,
+	0xff, 0x18, 0x5e, 0x9a, 0xb9, 0xf4, 0xac, 0x47, 0xf0, 0x62, 0x61, 0x0d, 0xea, 0x7f, 0x84, 0xde,
+	0xc2, 0x2e, 0x40, 0x03, 0x68, 0x26, 0x24, 0xc6, 0xff, 0xa8, 0xa3, 0x7a, 0x41, 0x11, 0xa0, 0xaf,
+	0xa1, 0xa9, 0x78, 0xf4, 0xac, 0x2f, 0xc7, 0xe5, 0x1a, 0x17, 0x51, 0x98, 0x86, 0x2c, 0x28, 0xb2,
+	0x7e, 0x0b, 0x0e, 0xe6, 0x09, 0x59, 0xa9, 0x5f, 0x4a, 0x56, 0x3e, 0x82, 0xfe, 0x3c, 0x5f, 0xa6,
+	0x49, 0x34, 0xc3, 0x0f, 0xe5, 0x89, 0xef, 0xe0, 0xd8, 0xc2, 0xf4, 0xa9, 0xdf, 0x40, 0x2b, 0xcb,
+	0x97, 0x33, 0xfc, 0xa0, 0xaf, 0xfc, 0xc8, 0x1c, 0x30, 0xa7, 0x09, 0x11, 0x81, 0xce, 0xfa, 0x27,
+	0x70, 0x3c, 0x67, 0xc9, 0xa7, 0x50, 0x60, 0x8b, 0xf1, 0x17, 0x40, 0x36, 0xa8, 0x29, 0xdf, 0x42,
+	0x2b, 0x63, 0x49, 0x45, 0xf9, 0xa8, 0x67, 0x9d, 0x96, 0x57, 0x72, 0x45, 0xef, 0x2a, 0xba, 0x1f,
+	0xa1, 0xa7, 0x63, 0xcd, 0x74, 0x0e, 0xcd, 0x88, 0x3e, 0xdd, 0x5b, 0x91, 0xf4, 0xcf, 0xe1, 0x85,
+	0xda, 0x57, 0x29, 0xa1, 0x01, 0x34, 0x71, 0x46, 0xa3, 0x75, 0x79, 0x91, 0x2a, 0xf0, 0xbf, 0x87,
+	0x9e, 0xae, 0xd2, 0xe4, 0x6f, 0xa0, 0xa3, 0xf4, 0xfe, 0x27, 0xdd, 0xa4, 0xc5, 0x7a, 0x83, 0x0a,
+	0xf0, 0x07, 0x80, 0xae, 0x28, 0x21, 0x38, 0x92, 0xfb, 0xe4, 0x65, 0x87, 0xff, 0x39, 0xd0, 0xaf,
+	0xe0, 0x85, 0x08, 0x45, 0xce, 0xa5, 0x92, 0xc2, 0x38, 0x66, 0x98, 0x73, 0x4d, 0x53, 0x86, 0xa8,
+	0x0f, 0x0d, 0x91, 0x72, 0xed, 0x0f, 0xf9, 0x57, 0xf6, 0xc6, 0x45, 0x28, 0xb0, 0x36, 0x46, 0x11,
+	0x20, 0x0f, 0xda, 0xb7, 0x61, 0x92, 0xe6, 0x0c, 0x73, 0x25, 0xc6, 0x5e, 0x60, 0x62, 0xf4, 0x25,
+	0x40, 0x1a, 0x72, 0x71, 0x83, 0x19, 0xa3, 0xcc, 0x6d, 0x16, 0x7d, 0x4a, 0xe4, 0x37, 0x09, 0xa0,
+	0xaf, 0x00, 0x18, 0x8e, 0x8a, 0x96, 0xb8, 0xdb, 0x52, 0x0f, 0x5b, 0x88, 0x1f, 0xc0, 0x49, 0x6d,
+	0x0e, 0x3d, 0xfc, 0x3b, 0xe8, 0x46, 0x15, 0xec, 0x3a, 0xc3, 0xc6, 0xa8, 0x7b, 0xf1, 0xda, 0x18,
+	0x69, 0x7b, 0xc6, 0xc0, 0xae, 0x96, 0xea, 0x5e, 0xac, 0x73, 0x11, 0xd3, 0x7b, 0x52, 0x5e, 0x0c,
+	0x82, 0x7e, 0x05, 0x69, 0xc5, 0x07, 0xd0, 0x0f, 0xa8, 0xa8, 0x29, 0xa6, 0x34, 0xae, 0xf3, 0xbc,
+	0x71, 0x2d, 0x6f, 0xee, 0xd7, 0xbd, 0x79, 0x02, 0xc7, 0x16, 0x67, 0x71, 0xd0, 0xc5, 0xff, 0x4d,
+	0x38, 0xbc, 0x2a, 0x98, 0xd0, 0x77, 0xd0, 0x96, 0x46, 0x90, 0x26, 0x40, 0x3d, 0xc3, 0x2f, 0x21,
+	0xcf, 0x0a, 0xa5, 0x45, 0xf6, 0xd0, 0x4f, 0x70, 0xf8, 0x81, 0x24, 0xe2, 0x7a, 0x36, 0x45, 0x27,
+	0x26, 0x57, 0xbd, 0x15, 0xbd, 0x41, 0x1d, 0xd4, 0x63, 0xed, 0xa1, 0x4b, 0xe8, 0xca, 0xe7, 0xb4,
+	0xc3, 0xd1, 0x17, 0xa6, 0xac, 0xfe, 0x2e, 0xf3, 0xdc, 0xc7, 0x09, 0xc3, 0xf1, 0x33, 0x34, 0x95,
+	0xfd, 0xd1, 0xa9, 0x29, 0xb2, 0x5f, 0x0f, 0xde, 0xab, 0x6d, 0xd8, 0x3c, 0x79, 0x0d, 0x1d, 0x63,
+	0x63, 0x54, 0xad, 0x6c, 0xdb, 0xee, 0x9e, 0xb7, 0x2b, 0x65, 0x58, 0xa6, 0x00, 0x95, 0x75, 0x91,
+	0x55, 0xbb, 0x6d, 0x72, 0xef, 0x6c, 0x67, 0xce, 0x10, 0xfd, 0x2a, 0x4d, 0x9b, 0xa6, 0x52, 0x1b,
+	0x9f, 0x14, 0xd7, 0xa9, 0xa5, 0xa2, 0xca, 0xdc, 0xde, 0xab, 0x6d, 0xd8, 0xbe, 0x8a, 0xe2, 0xa3,
+	0x72, 0x5a, 0xd7, 0xc3, 0xe3, 0x27, 0x6b, 0x06, 0xf6, 0xf7, 0xd0, 0x1f, 0xd0, 0xb5, 0xc4, 0x8d,
+	0xce, 0x76, 0xe8, 0xb7, 0xb4, 0xae, 0xf7, 0x66, 0x77, 0xd2, 0x70, 0xbd, 0x87, 0x76, 0xa9, 0x60,
+	0xe4, 0x5a, 0x97, 0x5f, 0xd3, 0xb9, 0xf7, 0x7a, 0x47, 0xc6, 0xde, 0x8c, 0x11, 0xa7, 0xb5, 0x99,
+	0x6d, 0x13, 0x78, 0xde, 0xae, 0x54, 0xc9, 0x72, 0xf9, 0xed, 0x5f, 0x6f, 0x57, 0x89, 0x58, 0xe7,
+	0xcb, 0x71, 0x44, 0x37, 0x93, 0x18, 0xc7, 0x09, 0x9f, 0xc4, 0x2c, 0x24, 0xf1, 0x44, 0x7d, 0xb9,
+	0x97, 0xf9, 0x6d, 0xf9, 0xa5, 0x5f, 0xb6, 0x14, 0xf2, 0xc3, 0xe7, 0x01, 0x00, 0x58, 0xd1, 0x4c,
+	0x3a, 0x03, 0x08, 0x00, 0x00,
 }
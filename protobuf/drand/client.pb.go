@@ -31,7 +31,12 @@ type PublicRandRequest struct {
 	// round uniquely identifies a beacon. If round == 0, then the response will
 	// contain the last.
 	// XXX better ways to do that...
-	Round                uint64   `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Round uint64 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	// time, in seconds since the UNIX epoch, requests the beacon of the first
+	// round due at or after that time, as computed from the genesis time and
+	// the period of the group. It can not be set together with round. From the
+	// REST API, it is given as /api/public?time=...
+	Time                 int64    `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -68,17 +73,28 @@ func (m *PublicRandRequest) GetRound() uint64 {
 	return 0
 }
 
+func (m *PublicRandRequest) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
 // PublicRandResponse holds a signature which is the random value. It can be
 // verified thanks to the distributed public key of the nodes that have ran the
 // DKG protocol and is unbiasable. The randomness can be verified using the BLS
 // verification routine with the message "round || previous_rand".
 type PublicRandResponse struct {
-	Round                uint64        `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
-	Previous             []byte        `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
-	Randomness           *crypto.Point `protobuf:"bytes,3,opt,name=randomness,proto3" json:"randomness,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+	Round      uint64        `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Previous   []byte        `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	Randomness *crypto.Point `protobuf:"bytes,3,opt,name=randomness,proto3" json:"randomness,omitempty"`
+	// timestamp is the time, in seconds since the UNIX epoch, at which the
+	// round is due, as computed from the genesis time and the period of the
+	// group. It is 0 if the rounds of the group are not tied to time.
+	Timestamp            int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *PublicRandResponse) Reset()         { *m = PublicRandResponse{} }
@@ -126,6 +142,13 @@ func (m *PublicRandResponse) GetRandomness() *crypto.Point {
 	return nil
 }
 
+func (m *PublicRandResponse) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 // PrivateRandRequest is the message to send when requesting a private random
 // value.
 type PrivateRandRequest struct {
@@ -264,6 +287,11 @@ func (m *ECIESObject) GetNonce() []byte {
 
 // DistKeyRequest requests the distributed public key used during the randomness generation process
 type DistKeyRequest struct {
+	// round requests the distributed key of the group that produced the given
+	// round, as kept in the history of the node, instead of the current one
+	// when it is 0. From the REST API, it is given as
+	// /api/info/distkey?round=...
+	Round                uint64   `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -293,6 +321,13 @@ func (m *DistKeyRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DistKeyRequest proto.InternalMessageInfo
 
+func (m *DistKeyRequest) GetRound() uint64 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
 type DistKeyResponse struct {
 	Key                  *crypto.Point `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -399,6 +434,264 @@ func (m *HomeResponse) GetStatus() string {
 	return ""
 }
 
+// GroupRequest requests the current public group of the node.
+type GroupRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupRequest) Reset()         { *m = GroupRequest{} }
+func (m *GroupRequest) String() string { return proto.CompactTextString(m) }
+func (*GroupRequest) ProtoMessage()    {}
+func (*GroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_client_b0e2f19983be69fc, []int{9}
+}
+func (m *GroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupRequest.Unmarshal(m, b)
+}
+func (m *GroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupRequest.Marshal(b, m, deterministic)
+}
+func (dst *GroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupRequest.Merge(dst, src)
+}
+func (m *GroupRequest) XXX_Size() int {
+	return xxx_messageInfo_GroupRequest.Size(m)
+}
+func (m *GroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupRequest proto.InternalMessageInfo
+
+// GroupResponse holds the public group of the node, with all the information
+// needed to contact its nodes and verify its beacons.
+type GroupResponse struct {
+	// hash is the chain hash of the group, covering the distributed key, the
+	// period, the genesis time, the beacon mode, the scheme and the genesis
+	// message of the beacons. Clients can pin it to make sure they follow the
+	// right chain.
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// hash_version is the version of the encoding of the chain hash.
+	HashVersion uint32  `protobuf:"varint,2,opt,name=hash_version,json=hashVersion,proto3" json:"hash_version,omitempty"`
+	Nodes       []*Node `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Threshold   uint32  `protobuf:"varint,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// period between two beacons, in the Go duration format, e.g. "1m0s"
+	Period string `protobuf:"bytes,5,opt,name=period,proto3" json:"period,omitempty"`
+	// dist_key holds the coefficients of the distributed public key, the
+	// first one being the key verifying the beacons.
+	DistKey   []*crypto.Point `protobuf:"bytes,6,rep,name=dist_key,json=distKey,proto3" json:"dist_key,omitempty"`
+	Unchained bool            `protobuf:"varint,7,opt,name=unchained,proto3" json:"unchained,omitempty"`
+	Scheme    string          `protobuf:"bytes,8,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	KeyGroup  string          `protobuf:"bytes,9,opt,name=key_group,json=keyGroup,proto3" json:"key_group,omitempty"`
+	// genesis_time is the time, in seconds since the UNIX epoch, at which the
+	// first round is due.
+	GenesisTime          int64    `protobuf:"varint,10,opt,name=genesis_time,json=genesisTime,proto3" json:"genesis_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupResponse) Reset()         { *m = GroupResponse{} }
+func (m *GroupResponse) String() string { return proto.CompactTextString(m) }
+func (*GroupResponse) ProtoMessage()    {}
+func (*GroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_client_b0e2f19983be69fc, []int{10}
+}
+func (m *GroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupResponse.Unmarshal(m, b)
+}
+func (m *GroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupResponse.Marshal(b, m, deterministic)
+}
+func (dst *GroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupResponse.Merge(dst, src)
+}
+func (m *GroupResponse) XXX_Size() int {
+	return xxx_messageInfo_GroupResponse.Size(m)
+}
+func (m *GroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupResponse proto.InternalMessageInfo
+
+func (m *GroupResponse) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *GroupResponse) GetHashVersion() uint32 {
+	if m != nil {
+		return m.HashVersion
+	}
+	return 0
+}
+
+func (m *GroupResponse) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *GroupResponse) GetThreshold() uint32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *GroupResponse) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+func (m *GroupResponse) GetDistKey() []*crypto.Point {
+	if m != nil {
+		return m.DistKey
+	}
+	return nil
+}
+
+func (m *GroupResponse) GetUnchained() bool {
+	if m != nil {
+		return m.Unchained
+	}
+	return false
+}
+
+func (m *GroupResponse) GetScheme() string {
+	if m != nil {
+		return m.Scheme
+	}
+	return ""
+}
+
+func (m *GroupResponse) GetKeyGroup() string {
+	if m != nil {
+		return m.KeyGroup
+	}
+	return ""
+}
+
+func (m *GroupResponse) GetGenesisTime() int64 {
+	if m != nil {
+		return m.GenesisTime
+	}
+	return 0
+}
+
+// Node is the public identity of a node of the group, as in group.toml.
+type Node struct {
+	Address        string        `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Key            *crypto.Point `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Tls            bool          `protobuf:"varint,3,opt,name=tls,proto3" json:"tls,omitempty"`
+	Transport      string        `protobuf:"bytes,4,opt,name=transport,proto3" json:"transport,omitempty"`
+	Name           string        `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Contact        string        `protobuf:"bytes,6,opt,name=contact,proto3" json:"contact,omitempty"`
+	Region         string        `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	TlsFingerprint string        `protobuf:"bytes,8,opt,name=tls_fingerprint,json=tlsFingerprint,proto3" json:"tls_fingerprint,omitempty"`
+	// signature is the self-signature of the identity by the node, empty for
+	// nodes that did not sign it.
+	Signature            []byte   `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Node) Reset()         { *m = Node{} }
+func (m *Node) String() string { return proto.CompactTextString(m) }
+func (*Node) ProtoMessage()    {}
+func (*Node) Descriptor() ([]byte, []int) {
+	return fileDescriptor_client_b0e2f19983be69fc, []int{11}
+}
+func (m *Node) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Node.Unmarshal(m, b)
+}
+func (m *Node) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Node.Marshal(b, m, deterministic)
+}
+func (dst *Node) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Node.Merge(dst, src)
+}
+func (m *Node) XXX_Size() int {
+	return xxx_messageInfo_Node.Size(m)
+}
+func (m *Node) XXX_DiscardUnknown() {
+	xxx_messageInfo_Node.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Node proto.InternalMessageInfo
+
+func (m *Node) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Node) GetKey() *crypto.Point {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Node) GetTls() bool {
+	if m != nil {
+		return m.Tls
+	}
+	return false
+}
+
+func (m *Node) GetTransport() string {
+	if m != nil {
+		return m.Transport
+	}
+	return ""
+}
+
+func (m *Node) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Node) GetContact() string {
+	if m != nil {
+		return m.Contact
+	}
+	return ""
+}
+
+func (m *Node) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Node) GetTlsFingerprint() string {
+	if m != nil {
+		return m.TlsFingerprint
+	}
+	return ""
+}
+
+func (m *Node) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*PublicRandRequest)(nil), "drand.PublicRandRequest")
 	proto.RegisterType((*PublicRandResponse)(nil), "drand.PublicRandResponse")
@@ -409,6 +702,9 @@ func init() {
 	proto.RegisterType((*DistKeyResponse)(nil), "drand.DistKeyResponse")
 	proto.RegisterType((*HomeRequest)(nil), "drand.HomeRequest")
 	proto.RegisterType((*HomeResponse)(nil), "drand.HomeResponse")
+	proto.RegisterType((*GroupRequest)(nil), "drand.GroupRequest")
+	proto.RegisterType((*GroupResponse)(nil), "drand.GroupResponse")
+	proto.RegisterType((*Node)(nil), "drand.Node")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -522,6 +818,7 @@ var _Randomness_serviceDesc = grpc.ServiceDesc{
 type InfoClient interface {
 	DistKey(ctx context.Context, in *DistKeyRequest, opts ...grpc.CallOption) (*DistKeyResponse, error)
 	Home(ctx context.Context, in *HomeRequest, opts ...grpc.CallOption) (*HomeResponse, error)
+	Group(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupResponse, error)
 }
 
 type infoClient struct {
@@ -550,10 +847,20 @@ func (c *infoClient) Home(ctx context.Context, in *HomeRequest, opts ...grpc.Cal
 	return out, nil
 }
 
+func (c *infoClient) Group(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, "/drand.Info/Group", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InfoServer is the server API for Info service.
 type InfoServer interface {
 	DistKey(context.Context, *DistKeyRequest) (*DistKeyResponse, error)
 	Home(context.Context, *HomeRequest) (*HomeResponse, error)
+	Group(context.Context, *GroupRequest) (*GroupResponse, error)
 }
 
 func RegisterInfoServer(s *grpc.Server, srv InfoServer) {
@@ -596,6 +903,24 @@ func _Info_Home_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Info_Group_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).Group(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Info/Group",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).Group(ctx, req.(*GroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Info_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "drand.Info",
 	HandlerType: (*InfoServer)(nil),
@@ -608,6 +933,10 @@ var _Info_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Home",
 			Handler:    _Info_Home_Handler,
 		},
+		{
+			MethodName: "Group",
+			Handler:    _Info_Group_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "drand/client.proto",
@@ -616,38 +945,59 @@ var _Info_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("drand/client.proto", fileDescriptor_client_b0e2f19983be69fc) }
 
 var fileDescriptor_client_b0e2f19983be69fc = []byte{
-	// 528 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xcf, 0x6e, 0xd3, 0x40,
-	0x10, 0xc6, 0xe5, 0x36, 0x4d, 0xda, 0x49, 0x28, 0x64, 0xd2, 0x96, 0xd4, 0x42, 0x28, 0xb2, 0xf8,
-	0x13, 0xaa, 0xca, 0x96, 0xd2, 0x1b, 0x12, 0x97, 0x42, 0x24, 0x2a, 0x0e, 0x44, 0xee, 0x89, 0xde,
-	0x1c, 0x7b, 0x92, 0x2c, 0x4d, 0x76, 0x5d, 0xef, 0x3a, 0x22, 0x42, 0x5c, 0x78, 0x05, 0x6e, 0x3c,
-	0x16, 0xbc, 0x02, 0x0f, 0x82, 0xb2, 0xbb, 0x31, 0x4e, 0x1b, 0x7a, 0xf3, 0x37, 0xf3, 0xf9, 0xb7,
-	0xb3, 0x9f, 0xc7, 0x80, 0x49, 0x16, 0xf1, 0x24, 0x88, 0xa7, 0x8c, 0xb8, 0xf2, 0xd3, 0x4c, 0x28,
-	0x81, 0x3b, 0xba, 0xe6, 0x1e, 0xc4, 0xd9, 0x22, 0x55, 0x22, 0xa0, 0x29, 0xcd, 0x8a, 0xa6, 0xfb,
-	0x64, 0x2c, 0xc4, 0x78, 0x4a, 0x41, 0x94, 0xb2, 0x20, 0xe2, 0x5c, 0xa8, 0x48, 0x31, 0xc1, 0xa5,
-	0xe9, 0x7a, 0xaf, 0xa0, 0x39, 0xc8, 0x87, 0x53, 0x16, 0x87, 0x11, 0x4f, 0x42, 0xba, 0xc9, 0x49,
-	0x2a, 0x3c, 0x80, 0x9d, 0x4c, 0xe4, 0x3c, 0x69, 0x3b, 0x1d, 0xa7, 0x5b, 0x09, 0x8d, 0xf0, 0xe6,
-	0x80, 0x65, 0xab, 0x4c, 0x05, 0x97, 0xb4, 0xd9, 0x8b, 0x2e, 0xec, 0xa6, 0x19, 0xcd, 0x99, 0xc8,
-	0x65, 0x7b, 0xab, 0xe3, 0x74, 0x1b, 0x61, 0xa1, 0xd1, 0x07, 0x58, 0x8e, 0x2b, 0x66, 0x9c, 0xa4,
-	0x6c, 0x6f, 0x77, 0x9c, 0x6e, 0xbd, 0xb7, 0xef, 0xaf, 0x86, 0x1e, 0x08, 0xc6, 0x55, 0x58, 0x72,
-	0x78, 0xe7, 0x80, 0x83, 0x8c, 0xcd, 0x23, 0x45, 0xe5, 0x19, 0x4f, 0xa1, 0x96, 0x99, 0x47, 0x7d,
-	0x72, 0xbd, 0x87, 0xbe, 0x4e, 0xc1, 0xef, 0xbf, 0xbd, 0xe8, 0x5f, 0x7e, 0x1c, 0x7e, 0xa6, 0x58,
-	0x85, 0x2b, 0x8b, 0xd7, 0x87, 0xd6, 0x1a, 0xc3, 0x0e, 0xef, 0xc3, 0x6e, 0x66, 0x9f, 0xef, 0xa1,
-	0x14, 0x1e, 0xef, 0x06, 0xea, 0xa5, 0x06, 0x9e, 0xc2, 0x1e, 0xa5, 0x13, 0x9a, 0x51, 0x16, 0x4d,
-	0xed, 0xfb, 0xb7, 0x2f, 0xf2, 0xcf, 0x80, 0x4f, 0x01, 0x62, 0x96, 0x4e, 0x28, 0x53, 0xf4, 0x45,
-	0xd9, 0x54, 0x4a, 0x95, 0x65, 0x92, 0x5c, 0xf0, 0x98, 0x74, 0x24, 0x8d, 0xd0, 0x08, 0xef, 0x11,
-	0xec, 0xbf, 0x63, 0x52, 0x7d, 0xa0, 0x85, 0xbd, 0xb9, 0x77, 0x06, 0x0f, 0x8b, 0x8a, 0xbd, 0x47,
-	0x07, 0xb6, 0xaf, 0x69, 0xf1, 0x9f, 0x11, 0x96, 0x2d, 0xef, 0x01, 0xd4, 0xdf, 0x8b, 0x19, 0xad,
-	0x18, 0x2f, 0xa0, 0x61, 0xa4, 0x05, 0x1c, 0x41, 0x55, 0xaa, 0x48, 0xe5, 0x52, 0x33, 0xf6, 0x42,
-	0xab, 0x7a, 0xbf, 0x1c, 0x80, 0xb0, 0xf8, 0x14, 0xc8, 0xa0, 0x6a, 0x56, 0x00, 0xdb, 0x36, 0xa7,
-	0x3b, 0xcb, 0xe3, 0x1e, 0x6f, 0xe8, 0xd8, 0xf8, 0x4e, 0xbe, 0xff, 0xfe, 0xf3, 0x63, 0xeb, 0x19,
-	0xd6, 0xf5, 0x32, 0xa6, 0xda, 0x70, 0x75, 0x88, 0xad, 0x92, 0x0c, 0xbe, 0xea, 0x05, 0xfa, 0x86,
-	0x9f, 0xa0, 0x66, 0xbf, 0x18, 0x16, 0xc4, 0x3b, 0x5b, 0xe0, 0xba, 0x9b, 0x5a, 0xf6, 0xb4, 0xc7,
-	0xfa, 0xb4, 0xa6, 0xd7, 0x30, 0x78, 0xe3, 0x78, 0xed, 0x9c, 0xf4, 0x7e, 0x3a, 0x50, 0xb9, 0xe0,
-	0x23, 0x81, 0x97, 0x50, 0xb3, 0x49, 0xe2, 0xa1, 0x05, 0xad, 0x67, 0xed, 0x1e, 0xdd, 0x2e, 0x5b,
-	0xf6, 0xb1, 0x66, 0xb7, 0xb0, 0xa9, 0xd9, 0x8c, 0x8f, 0x44, 0x90, 0x30, 0xa9, 0xae, 0x69, 0x81,
-	0x6f, 0xa0, 0xb2, 0x8c, 0x16, 0x57, 0x9b, 0x54, 0x8a, 0xdd, 0x6d, 0xad, 0xd5, 0x2c, 0xab, 0xa1,
-	0x59, 0x55, 0xac, 0x2c, 0x59, 0xe7, 0x2f, 0xaf, 0x9e, 0x8f, 0x99, 0x9a, 0xe4, 0x43, 0x3f, 0x16,
-	0xb3, 0x20, 0xa1, 0x84, 0xc9, 0xc0, 0xfc, 0xf2, 0xfa, 0x87, 0x1d, 0xe6, 0x23, 0x23, 0x87, 0x55,
-	0xad, 0xcf, 0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0xd2, 0xe2, 0x59, 0xee, 0x11, 0x04, 0x00, 0x00,
+	// 860 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x5d, 0x8e, 0x1b, 0x45,
+	0x10, 0xd6, 0xac, 0xbd, 0xfe, 0xa9, 0xf1, 0xee, 0xb2, 0xbd, 0x9b, 0x30, 0x19, 0x22, 0xe4, 0x8c,
+	0x20, 0x31, 0x51, 0x64, 0x4b, 0xce, 0x1b, 0x52, 0x5e, 0x02, 0x0b, 0x44, 0x91, 0x60, 0xd5, 0x41,
+	0x48, 0xe4, 0xc5, 0x1a, 0xcf, 0xd4, 0xda, 0xcd, 0x8e, 0xbb, 0x27, 0xdd, 0x3d, 0x2b, 0x2c, 0xc4,
+	0x0b, 0x57, 0x80, 0x03, 0x71, 0x06, 0xb8, 0x02, 0x12, 0xc7, 0x00, 0xf5, 0x8f, 0x3d, 0xe3, 0xc4,
+	0x9b, 0x27, 0x77, 0x7d, 0x55, 0xf3, 0x75, 0xd5, 0x57, 0x55, 0x6d, 0x20, 0xb9, 0x4c, 0x79, 0x3e,
+	0xc9, 0x0a, 0x86, 0x5c, 0x8f, 0x4b, 0x29, 0xb4, 0x20, 0x87, 0x16, 0x8b, 0xcf, 0x33, 0xb9, 0x2e,
+	0xb5, 0x98, 0x60, 0x81, 0xab, 0xad, 0x33, 0xbe, 0xbf, 0x10, 0x62, 0x51, 0xe0, 0x24, 0x2d, 0xd9,
+	0x24, 0xe5, 0x5c, 0xe8, 0x54, 0x33, 0xc1, 0x95, 0xf3, 0x26, 0xcf, 0xe0, 0xf4, 0xb2, 0x9a, 0x17,
+	0x2c, 0xa3, 0x29, 0xcf, 0x29, 0xbe, 0xa9, 0x50, 0x69, 0x72, 0x0e, 0x87, 0x52, 0x54, 0x3c, 0x8f,
+	0x82, 0x61, 0x30, 0x6a, 0x53, 0x67, 0x10, 0x02, 0x6d, 0xcd, 0x56, 0x18, 0x1d, 0x0c, 0x83, 0x51,
+	0x8b, 0xda, 0x73, 0xf2, 0x47, 0x00, 0xa4, 0xf9, 0xbd, 0x2a, 0x05, 0x57, 0x78, 0x0b, 0x41, 0x0c,
+	0xbd, 0x52, 0xe2, 0x0d, 0x13, 0x95, 0xb2, 0x24, 0x03, 0xba, 0xb5, 0xc9, 0x18, 0xc0, 0xd4, 0x20,
+	0x56, 0x1c, 0x95, 0x8a, 0x5a, 0xc3, 0x60, 0x14, 0x4e, 0x8f, 0xc7, 0x9b, 0x4a, 0x2e, 0x05, 0xe3,
+	0x9a, 0x36, 0x22, 0xc8, 0x7d, 0xe8, 0x9b, 0x04, 0x94, 0x4e, 0x57, 0x65, 0xd4, 0xb6, 0x19, 0xd5,
+	0x40, 0xf2, 0x1c, 0xc8, 0xa5, 0x64, 0x37, 0xa9, 0xc6, 0x66, 0x59, 0x4f, 0xa0, 0x2b, 0xdd, 0xd1,
+	0xe6, 0x15, 0x4e, 0xc9, 0xd8, 0x0a, 0x37, 0xbe, 0xf8, 0xe2, 0xc5, 0xc5, 0xab, 0xef, 0xe6, 0x3f,
+	0x61, 0xa6, 0xe9, 0x26, 0x24, 0xb9, 0x80, 0xb3, 0x1d, 0x0e, 0x5f, 0xda, 0x18, 0x7a, 0xd2, 0x9f,
+	0xdf, 0xc3, 0xb2, 0x8d, 0x49, 0xde, 0x40, 0xd8, 0x70, 0x90, 0x27, 0xd0, 0xc7, 0x72, 0x89, 0x2b,
+	0x94, 0x69, 0x11, 0x05, 0x7b, 0xcb, 0xac, 0x03, 0xc8, 0xc7, 0x00, 0x19, 0x2b, 0x97, 0x28, 0x35,
+	0xfe, 0xac, 0xbd, 0x66, 0x0d, 0xc4, 0xe8, 0xcc, 0x05, 0xcf, 0xd0, 0x0a, 0x36, 0xa0, 0xce, 0x48,
+	0x1e, 0xc2, 0xf1, 0x97, 0x4c, 0xe9, 0x97, 0xb8, 0x7e, 0x6f, 0x43, 0x93, 0xa7, 0x70, 0xb2, 0x8d,
+	0xf3, 0xd5, 0x0d, 0xa1, 0x75, 0x8d, 0xeb, 0x5b, 0x12, 0x33, 0xae, 0xe4, 0x08, 0xc2, 0x6f, 0xc4,
+	0x0a, 0x3d, 0x73, 0xf2, 0x10, 0x06, 0xce, 0xf4, 0x04, 0x77, 0xa1, 0xa3, 0x74, 0xaa, 0x2b, 0x65,
+	0x39, 0xfa, 0xd4, 0x5b, 0xc9, 0x31, 0x0c, 0xbe, 0x96, 0xa2, 0x2a, 0x37, 0xdf, 0xfd, 0x79, 0x00,
+	0x47, 0x1e, 0xf0, 0x5f, 0x12, 0x68, 0x2f, 0x53, 0xb5, 0xf4, 0xdf, 0xd9, 0x33, 0x79, 0x00, 0x03,
+	0xf3, 0x3b, 0xbb, 0x41, 0xa9, 0x98, 0xe0, 0x56, 0x81, 0x23, 0x1a, 0x1a, 0xec, 0x07, 0x07, 0x91,
+	0x07, 0x46, 0x82, 0x1c, 0xcd, 0xcc, 0xb4, 0x46, 0xe1, 0x34, 0xf4, 0xcd, 0xf8, 0x56, 0xe4, 0x48,
+	0x9d, 0xc7, 0xce, 0xca, 0x52, 0xa2, 0x5a, 0x8a, 0x22, 0xb7, 0xb3, 0x72, 0x44, 0x6b, 0xc0, 0x64,
+	0x5c, 0xa2, 0x64, 0x22, 0x8f, 0x0e, 0x5d, 0xc6, 0xce, 0x22, 0x9f, 0x41, 0x2f, 0x67, 0x4a, 0xcf,
+	0x8c, 0x1e, 0x9d, 0x61, 0x6b, 0x8f, 0x1e, 0xdd, 0xdc, 0xa9, 0x67, 0x2e, 0xa8, 0x78, 0xb6, 0x4c,
+	0x19, 0xc7, 0x3c, 0xea, 0x0e, 0x83, 0x51, 0x8f, 0xd6, 0x80, 0x95, 0x24, 0x33, 0x1d, 0x8d, 0x7a,
+	0x5e, 0x12, 0x6b, 0x91, 0x8f, 0xa0, 0x7f, 0x8d, 0xeb, 0xd9, 0xc2, 0xa8, 0x10, 0xf5, 0xad, 0xab,
+	0x77, 0x8d, 0x6b, 0xab, 0x8a, 0xa9, 0x7c, 0x81, 0x1c, 0x15, 0x53, 0x33, 0xbb, 0x74, 0x60, 0x47,
+	0x3c, 0xf4, 0xd8, 0xf7, 0x66, 0xf7, 0xfe, 0x0b, 0xa0, 0x6d, 0xca, 0x24, 0x11, 0x74, 0xd3, 0x3c,
+	0x97, 0x66, 0x71, 0x9c, 0x78, 0x1b, 0x73, 0xd3, 0xce, 0x83, 0x5b, 0xdb, 0x49, 0x3e, 0x80, 0x96,
+	0x2e, 0xdc, 0xc2, 0xf5, 0xa8, 0x39, 0x5a, 0xb5, 0x64, 0xca, 0x55, 0x29, 0xa4, 0xb6, 0x6a, 0xf5,
+	0x69, 0x0d, 0x98, 0x2e, 0xf1, 0x74, 0x85, 0x5e, 0x2b, 0x7b, 0x36, 0xf7, 0x67, 0x82, 0xeb, 0x34,
+	0xd3, 0x51, 0xc7, 0xdd, 0xef, 0x4d, 0x53, 0xba, 0xc4, 0x85, 0xe9, 0x5c, 0xd7, 0x95, 0xee, 0x2c,
+	0xf2, 0x08, 0x4e, 0x74, 0xa1, 0x66, 0x57, 0x8c, 0x2f, 0x50, 0x96, 0x92, 0x71, 0xed, 0xb5, 0x39,
+	0xd6, 0x85, 0xfa, 0xaa, 0x46, 0x4d, 0x32, 0x8a, 0x2d, 0x78, 0xaa, 0x2b, 0x89, 0x56, 0xa3, 0x01,
+	0xad, 0x81, 0xe9, 0x5f, 0x01, 0x00, 0xad, 0xdf, 0x04, 0x06, 0x1d, 0xf7, 0x16, 0x91, 0xc8, 0x4f,
+	0xc1, 0x3b, 0x4f, 0x5b, 0x7c, 0x6f, 0x8f, 0xc7, 0x6f, 0xea, 0xe3, 0xdf, 0xfe, 0xfe, 0xe7, 0xf7,
+	0x83, 0x4f, 0x5e, 0xdf, 0x21, 0x67, 0xf6, 0xb1, 0x2c, 0x6d, 0xc8, 0xe4, 0x17, 0xbb, 0x2c, 0xbf,
+	0x92, 0xb0, 0x01, 0x92, 0x1f, 0xa1, 0xeb, 0x1f, 0x07, 0xb2, 0x65, 0x7c, 0xe7, 0xc1, 0x89, 0xe3,
+	0x7d, 0x2e, 0x7f, 0xdb, 0x87, 0xf6, 0xb6, 0xd3, 0x64, 0xe0, 0x68, 0x5d, 0xc4, 0xe7, 0xc1, 0xe3,
+	0xe9, 0xbf, 0x01, 0xb4, 0x5f, 0xf0, 0x2b, 0x41, 0x5e, 0x41, 0xd7, 0xaf, 0x27, 0xb9, 0xe3, 0x89,
+	0x76, 0xd7, 0x3a, 0xbe, 0xfb, 0x36, 0xec, 0xb9, 0xef, 0x59, 0xee, 0x33, 0x72, 0x6a, 0xb9, 0x19,
+	0xbf, 0x12, 0x13, 0x33, 0xaa, 0xa6, 0xdf, 0xcf, 0xa0, 0x6d, 0xf6, 0x95, 0x6c, 0x1e, 0xad, 0xc6,
+	0x2e, 0xc7, 0x67, 0x3b, 0x98, 0xe7, 0x1a, 0x58, 0xae, 0x0e, 0x69, 0x1b, 0x2e, 0xf2, 0x12, 0x0e,
+	0xdd, 0x7c, 0x6e, 0x62, 0x9b, 0x4b, 0x1d, 0x9f, 0xef, 0x82, 0xbb, 0x95, 0x92, 0x93, 0x3a, 0x1b,
+	0x3b, 0xf3, 0xcf, 0x1f, 0xbd, 0xfe, 0x74, 0xc1, 0xf4, 0xb2, 0x9a, 0x8f, 0x33, 0xb1, 0x9a, 0xe4,
+	0x98, 0x33, 0x35, 0x71, 0xff, 0x6e, 0xf6, 0xbf, 0x69, 0x5e, 0x5d, 0x39, 0x73, 0xde, 0xb1, 0xf6,
+	0xd3, 0xff, 0x07, 0x00, 0xe1, 0x08, 0xf7, 0x7a, 0xfc, 0x06, 0x00, 0x00,
 }
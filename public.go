@@ -1,7 +1,15 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/dedis/drand/core"
+	"github.com/dedis/drand/fs"
+	"github.com/dedis/drand/key"
 	"github.com/dedis/drand/net"
 	crypto "github.com/dedis/drand/protobuf/crypto"
 	"github.com/dedis/drand/protobuf/drand"
@@ -11,13 +19,16 @@ import (
 
 func getPrivateCmd(c *cli.Context) error {
 	if !c.Args().Present() {
-		slog.Fatal("Get private takes a group file as argument.")
+		fatalUsage("get private takes a group file as argument")
 	}
 	defaultManager := net.NewCertManager()
 	if c.IsSet("tls-cert") {
 		defaultManager.Add(c.String("tls-cert"))
 	}
 	ids := getNodes(c)
+	if ids[0].Key == nil {
+		fatalUsage("get private needs --nodes to select the key of the node given with --connect")
+	}
 	client := core.NewGrpcClientFromCert(defaultManager)
 	var resp []byte
 	var err error
@@ -38,13 +49,13 @@ func getPrivateCmd(c *cli.Context) error {
 		Randomness []byte
 	}
 
-	printJSON(&private{resp})
+	printData(c.App.Writer, c, &private{resp})
 	return nil
 }
 
 func getPublicCmd(c *cli.Context) error {
 	if !c.Args().Present() {
-		slog.Fatal("Get public command takes a group file as argument.")
+		fatalUsage("get public command takes a group file as argument")
 	}
 	defaultManager := net.NewCertManager()
 	if c.IsSet("tls-cert") {
@@ -57,25 +68,195 @@ func getPublicCmd(c *cli.Context) error {
 		slog.Fatalf("drand: group file must contain the distributed public key!")
 	}
 
-	public := group.PublicKey
+	if c.IsSet("round") && c.IsSet(timeFlag.Name) {
+		fatalUsage("--round and --time can not be used together")
+	}
+	var at time.Time
+	if c.IsSet(timeFlag.Name) {
+		at = parseTime(c.String(timeFlag.Name))
+	}
+
 	client := core.NewGrpcClientFromCert(defaultManager)
+	if c.Bool(restFlag.Name) {
+		client = core.NewRESTClientFromCert(defaultManager)
+	}
 	isTLS := !c.Bool("tls-disable")
+	nodes := make([]*key.Identity, len(ids))
+	for i, id := range ids {
+		nodes[i] = &key.Identity{Key: id.Key, Addr: id.Addr, TLS: isTLS}
+	}
+	groupClient, err := core.NewGroupClient(client, group,
+		core.WithGroupNodes(nodes...),
+		core.WithQuorum(c.Int(quorumFlag.Name)),
+		core.WithRetries(c.Int(retriesFlag.Name)))
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
 	var resp *drand.PublicRandResponse
-	var err error
-	for _, id := range ids {
-		if c.IsSet("round") {
-			resp, err = client.Public(id.Addr, public, isTLS, c.Int("round"))
-		} else {
-			resp, err = client.LastPublic(id.Addr, public, isTLS)
+	if c.IsSet("round") {
+		resp, err = groupClient.Public(c.Int("round"))
+	} else if c.IsSet(timeFlag.Name) {
+		resp, err = groupClient.PublicAt(at)
+	} else {
+		resp, err = groupClient.LastPublic()
+	}
+	if err != nil {
+		slog.Fatalf("drand: could not get public randomness: %s", err)
+	}
+	slog.Infof("drand: public randomness of round %d retrieved", resp.GetRound())
+
+	if c.Bool(deriveFlag.Name) || c.IsSet(rangeFlag.Name) || c.IsSet(shuffleFlag.Name) {
+		printData(c.App.Writer, c, deriveRandomness(c, client, resp))
+		return nil
+	}
+	printData(c.App.Writer, c, resp)
+	return nil
+}
+
+// parseTime reads a time given either in the RFC 3339 format or as a number of
+// seconds since the unix epoch.
+func parseTime(s string) time.Time {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0)
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		fatalUsage("invalid time %s: expected RFC 3339 or unix seconds", s)
+	}
+	return t
+}
+
+type derivedRandomness struct {
+	Round       uint64
+	Randomness  []byte
+	Range       *uint64 `json:",omitempty"`
+	Permutation []int   `json:",omitempty"`
+}
+
+// deriveRandomness computes the values requested by the derive flags. If both
+// a range and a permutation are requested, they are drawn in this order from
+// the same source.
+func deriveRandomness(c *cli.Context, client *core.Client, resp *drand.PublicRandResponse) *derivedRandomness {
+	rand, err := client.Randomness(resp)
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	d := &derivedRandomness{Round: resp.GetRound(), Randomness: rand}
+	source, err := client.Source(resp, c.String(contextFlag.Name))
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	if c.IsSet(rangeFlag.Name) {
+		bounds := strings.Split(c.String(rangeFlag.Name), ":")
+		if len(bounds) != 2 {
+			slog.Fatalf("drand: range must be given as min:max")
 		}
-		if err == nil {
-			slog.Infof("drand: public randomness retrieved from %s", id.Addr)
-			break
+		min, err1 := strconv.ParseUint(bounds[0], 10, 64)
+		max, err2 := strconv.ParseUint(bounds[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			fatalUsage("invalid range %s", c.String(rangeFlag.Name))
+		}
+		v, err := source.Range(min, max)
+		if err != nil {
+			slog.Fatalf("drand: %s", err)
+		}
+		d.Range = &v
+	}
+	if c.IsSet(shuffleFlag.Name) {
+		n := c.Int(shuffleFlag.Name)
+		if n <= 0 {
+			fatalUsage("shuffle needs a positive number of elements")
+		}
+		d.Permutation = source.Permutation(n)
+	}
+	return d
+}
+
+// getGroupCmd fetches the public group of the node at the given address and
+// saves it as a group file, once checked against the expected chain hash or
+// the known group file given: at least one of them must be given, as the node
+// contacted could serve any group.
+func getGroupCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatalUsage("get group command takes the address of a node as argument")
+	}
+	if !c.IsSet(chainHashFlag.Name) && !c.IsSet(knownGroupFlag.Name) {
+		fatalUsage("get group needs the expected chain hash with --chain-hash or the known group file with --group")
+	}
+	var known *key.Group
+	if c.IsSet(knownGroupFlag.Name) {
+		known = new(key.Group)
+		if err := key.Load(c.String(knownGroupFlag.Name), known); err != nil {
+			slog.Fatalf("drand: can't load the known group: %s", err)
+		}
+	}
+	out := c.String(outFlag.Name)
+	if out == "" {
+		out = "group.toml"
+		if exists, _ := fs.Exists(out); exists && !c.Bool(forceGroupFlag.Name) {
+			fatalUsage("%s already exists, give another file with --out or overwrite it with --force", out)
 		}
-		slog.Printf("drand: could not get public randomness from %s: %s", id.Addr, err)
 	}
+	defaultManager := net.NewCertManager()
+	if c.IsSet("tls-cert") {
+		defaultManager.Add(c.String("tls-cert"))
+	}
+	client := core.NewGrpcClientFromCert(defaultManager)
+	if c.Bool(restFlag.Name) {
+		client = core.NewRESTClientFromCert(defaultManager)
+	}
+	group, err := client.Group(c.Args().First(), !c.Bool("tls-disable"))
+	if err != nil {
+		slog.Fatalf("drand: could not get a valid group: %s", err)
+	}
+	hash, err := group.ChainHash(core.DefaultSeed)
+	if err != nil {
+		slog.Fatalf("drand: %s", err)
+	}
+	if c.IsSet(chainHashFlag.Name) && c.String(chainHashFlag.Name) != hash {
+		slog.Fatalf("drand: the chain hash of the group is %s, not the expected one", hash)
+	}
+	if known != nil {
+		if err := sameGroup(known, group); err != nil {
+			slog.Fatalf("drand: the group served does not match the known group: %s", err)
+		}
+	}
+	for _, id := range group.Nodes {
+		if !id.IsSigned() {
+			printInfo("drand: identity of %s is not self-signed", id.Addr)
+		}
+	}
+	if err := key.Save(out, group, false); err != nil {
+		slog.Fatalf("drand: can't write the group file: %s", err)
+	}
+	printInfo("drand: group of chain hash %s written to %s", hash, out)
+	return nil
+}
 
-	printJSON(resp)
+// sameGroup checks that the group served by a node has the nodes, threshold
+// and round times of the known group, and its distributed key if known.
+func sameGroup(known, served *key.Group) error {
+	if served.Threshold != known.Threshold {
+		return fmt.Errorf("threshold %d instead of %d", served.Threshold, known.Threshold)
+	}
+	if served.Len() != known.Len() {
+		return fmt.Errorf("%d nodes instead of %d", served.Len(), known.Len())
+	}
+	for _, id := range known.Nodes {
+		i, found := served.Index(id)
+		if !found {
+			return fmt.Errorf("node %s missing", id.Addr)
+		}
+		if n := served.Nodes[i]; n.Addr != id.Addr || n.TLS != id.TLS {
+			return fmt.Errorf("node %s served at %s", id.Addr, n.Addr)
+		}
+	}
+	if served.Period != known.Period || served.GenesisTime != known.GenesisTime {
+		return errors.New("different period or genesis time")
+	}
+	if known.PublicKey != nil && len(known.PublicKey.Coefficients) > 0 && !served.PublicKey.Key().Equal(known.PublicKey.Key()) {
+		return errors.New("different distributed key")
+	}
 	return nil
 }
 
@@ -93,12 +274,12 @@ func getCokeyCmd(c *cli.Context) error {
 		if err == nil {
 			break
 		}
-		slog.Printf("drand: error fetching distributed key from %s : %s",
+		printInfo("drand: error fetching distributed key from %s : %s",
 			id.Addr, err)
 	}
 	if dkey == nil {
 		slog.Fatalf("drand: can't retrieve dist. key from all nodes")
 	}
-	printJSON(dkey)
+	printData(c.App.Writer, c, dkey)
 	return nil
 }
@@ -4,9 +4,13 @@ import "github.com/dedis/drand/key"
 
 type KeyStore struct {
 	priv  *key.Pair
+	next  *key.Pair
 	share *key.Share
 	group *key.Group
 	dist  *key.DistPublic
+	// history of the epochs, and the shares kept, by epoch number
+	epochs []*key.Epoch
+	shares map[int]*key.Share
 }
 
 func NewKeyStore() key.Store {
@@ -47,3 +51,46 @@ func (k *KeyStore) SaveDistPublic(d *key.DistPublic) error {
 func (k *KeyStore) LoadDistPublic() (*key.DistPublic, error) {
 	return k.dist, nil
 }
+
+func (k *KeyStore) SaveEpoch(e *key.Epoch) error {
+	k.epochs = append(k.epochs, &key.Epoch{Number: e.Number, FromRound: e.FromRound, Group: e.Group})
+	if e.Share != nil {
+		if k.shares == nil {
+			k.shares = make(map[int]*key.Share)
+		}
+		k.shares[e.Number] = e.Share
+	}
+	return nil
+}
+
+func (k *KeyStore) LoadEpochs() ([]*key.Epoch, error) {
+	return k.epochs, nil
+}
+
+func (k *KeyStore) LoadEpochShare(n int) (*key.Share, error) {
+	s, ok := k.shares[n]
+	if !ok {
+		return nil, key.ErrAbsent
+	}
+	return s, nil
+}
+
+func (k *KeyStore) SaveNextKeyPair(p *key.Pair) error {
+	k.next = p
+	return nil
+}
+
+func (k *KeyStore) LoadNextKeyPair() (*key.Pair, error) {
+	if k.next == nil {
+		return nil, key.ErrAbsent
+	}
+	return k.next, nil
+}
+
+func (k *KeyStore) RotateKeyPair() error {
+	if k.next == nil {
+		return key.ErrAbsent
+	}
+	k.priv, k.next = k.next, nil
+	return nil
+}